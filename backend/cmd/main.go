@@ -1,67 +1,345 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/dat-G/MLServer_Dash/backend/internal/audit"
+	"github.com/dat-G/MLServer_Dash/backend/internal/auth"
+	"github.com/dat-G/MLServer_Dash/backend/internal/certs"
+	"github.com/dat-G/MLServer_Dash/backend/internal/cluster"
 	"github.com/dat-G/MLServer_Dash/backend/internal/config"
 	"github.com/dat-G/MLServer_Dash/backend/internal/docker"
+	"github.com/dat-G/MLServer_Dash/backend/internal/middleware"
 	"github.com/dat-G/MLServer_Dash/backend/internal/monitor"
 	"github.com/dat-G/MLServer_Dash/backend/internal/router"
 	ws "github.com/dat-G/MLServer_Dash/backend/internal/websocket"
 )
 
 func main() {
+	// 辅助子命令
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		runCommand(os.Args[1], os.Args[2:])
+		return
+	}
+
+	// 命令行参数，优先级高于 MLDASH_* 环境变量和配置文件
+	parseFlags()
+
 	// 加载配置
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
+	log.Printf("Config: %s", config.Path())
+
+	// 启动失败时以非零状态退出；最先注册，在其余清理逻辑之后执行
+	exitCode := 0
+	defer func() {
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	}()
+
+	// 根 context，收到 SIGINT / SIGTERM 时取消，所有后台任务随之停止
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// 初始化认证
+	auth.Init(cfg.Auth)
+
+	// 打开审计日志
+	audit.Init(cfg.Audit)
+	defer audit.Close()
 
 	// 初始化 GPU 监控
 	monitor.InitGPU()
 	defer monitor.Shutdown()
 
+	// 启动 CPU 采集器
+	monitor.InitCPUCollector(ctx)
+	defer monitor.StopCPUCollector()
+
+	// 网络接口展示选项
+	monitor.InitNetwork(cfg.Network)
+
 	// 初始化 Docker
 	if err := docker.Init(); err != nil {
 		log.Printf("Docker not available: %v", err)
 	}
 	defer docker.Close()
 
+	// agent 模式只采集并上报到 hub，不启动 Web 服务
+	if cfg.Cluster.Mode == config.ModeAgent {
+		// 标签、备注和网络接口展示选项修改后随下一次上报生效
+		config.OnChange(func(cfg *config.Config) {
+			monitor.InitNetwork(cfg.Network)
+			cluster.ApplyConfig(cfg.Cluster)
+		})
+		go config.Watch(ctx)
+
+		if err := cluster.RunAgent(ctx, cfg.Cluster); err != nil {
+			log.Printf("Agent: %v", err)
+		}
+		log.Println("Agent stopped")
+		return
+	}
+
+	// 启动目录占用统计
+	monitor.InitDirWatcher(ctx, cfg.Storage)
+	defer monitor.StopDirWatcher()
+
+	// 集群节点：本机采样，hub 模式下接收 agent 上报
+	cluster.Start(ctx, cfg.Cluster)
+
 	// 初始化 WebSocket Hub
-	ws.InitHub()
+	ws.InitHub(ctx, cfg)
 
 	// 启动广播器
-	ws.StartBroadcasters(cfg)
+	ws.StartBroadcasters(ctx, cfg)
 
 	// 设置路由
 	app := router.Setup(cfg)
 
+	// 配置文件变化时热加载，需要重启的配置项只记录日志
+	config.OnChange(func(cfg *config.Config) {
+		middleware.ApplyConfig(cfg)
+		// 认证配置变化后（启用认证、删除用户、降低角色、修改策略等）断开已建立的流，
+		// 客户端重连时按新配置重新认证和授权订阅
+		if auth.ApplyConfig(cfg.Auth) {
+			ws.DisconnectClients()
+		}
+		ws.ApplyConfig(cfg)
+		monitor.InitNetwork(cfg.Network)
+		cluster.ApplyConfig(cfg.Cluster)
+	})
+	go config.Watch(ctx)
+
 	// 启动信息
 	addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
+	scheme, wsScheme := "http", "ws"
+	if cfg.Server.TLS.Enabled {
+		scheme, wsScheme = "https", "wss"
+	}
 	log.Printf("%s starting...", cfg.App.AppName)
-	log.Printf("Web UI: http://%s", addr)
-	log.Printf("API: http://%s/api", addr)
-	log.Printf("WebSocket: ws://%s/api/ws", addr)
+	log.Printf("Web UI: %s://%s", scheme, addr)
+	log.Printf("API: %s://%s/api", scheme, addr)
+	log.Printf("WebSocket: %s://%s/api/ws", wsScheme, addr)
+	log.Printf("SSE: %s://%s/api/stream", scheme, addr)
 	if cfg.App.GithubURL != "" {
 		log.Printf("GitHub: %s", cfg.App.GithubURL)
 	}
 	log.Println()
 
-	// 启动服务器
+	server := &http.Server{
+		Addr:    addr,
+		Handler: app,
+	}
+	// 开始关闭时断开 WebSocket 和 SSE 客户端，否则长连接的请求会一直阻塞 Shutdown
+	server.RegisterOnShutdown(ws.CloseClients)
+	server.RegisterOnShutdown(cluster.CloseAgents)
+
+	// 启动服务器，TLS 配置或监听失败时通过 serverErr 返回，使延迟的清理逻辑能够执行
+	serverErr := make(chan error, 2)
+	redirectServer, stopCerts, err := startServer(cfg, server, serverErr)
+	if err != nil {
+		serverErr <- fmt.Errorf("failed to set up TLS: %w", err)
+	}
+	defer stopCerts()
+
+	// 等待中断信号或服务器出错
+	select {
+	case <-ctx.Done():
+		log.Println("Shutting down server...")
+	case err := <-serverErr:
+		log.Printf("Failed to start server: %v", err)
+		exitCode = 1
+	}
+	stop()
+
+	shutdown(server, redirectServer)
+}
+
+// startServer 在后台启动 HTTP 或 HTTPS 监听，监听失败时将错误发送到 serverErr
+// 启用 TLS 时同时启动证书热加载（返回的 stop 用于停止）和可选的 HTTP 跳转监听
+func startServer(cfg *config.Config, server *http.Server, serverErr chan<- error) (*http.Server, func(), error) {
+	if !cfg.Server.TLS.Enabled {
+		go func() {
+			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				serverErr <- err
+			}
+		}()
+		return nil, func() {}, nil
+	}
+
+	certManager, err := certs.NewManager(cfg.Server.TLS)
+	if err != nil {
+		return nil, func() {}, err
+	}
+	certManager.Start()
+	server.TLSConfig = certManager.TLSConfig()
+
+	var redirectServer *http.Server
+	if cfg.Server.TLS.RedirectHTTP {
+		redirectAddr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.TLS.HTTPPort)
+		log.Printf("Redirecting http://%s to HTTPS", redirectAddr)
+		redirectServer = &http.Server{
+			Addr:    redirectAddr,
+			Handler: middleware.HTTPSRedirect(cfg.Server.Port),
+		}
+		go func() {
+			if err := redirectServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("HTTP redirect listener stopped: %v", err)
+			}
+		}()
+	}
+
 	go func() {
-		if err := app.Run(addr); err != nil {
-			log.Fatalf("Failed to start server: %v", err)
+		if err := server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+			serverErr <- err
 		}
 	}()
+	return redirectServer, certManager.Stop, nil
+}
 
-	// 等待中断信号
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+// shutdownTimeout 等待请求处理完成和后台任务退出的最长时间
+const shutdownTimeout = 10 * time.Second
 
-	log.Println("Shutting down server...")
+// shutdown 按顺序停止服务：停止接受新连接并断开 WebSocket/SSE 客户端，
+// 等待进行中的请求完成，再等待采集器和写协程退出；
+// 之后由 main 中的 defer 依次关闭 Docker、采集器和审计日志
+func shutdown(server, redirectServer *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if redirectServer != nil {
+		redirectServer.Shutdown(ctx)
+	}
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Server shutdown timed out, closing remaining connections: %v", err)
+		server.Close()
+	}
+	if err := ws.Wait(ctx); err != nil {
+		log.Printf("Timed out waiting for WebSocket workers: %v", err)
+	}
+	cluster.Wait()
+	log.Println("Server stopped")
+}
+
+// setFlags 可重复的 --set key=value 参数
+type setFlags map[string]string
+
+func (f setFlags) String() string { return "" }
+
+func (f setFlags) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	f[key] = val
+	return nil
+}
+
+// parseFlags 解析命令行参数并设置配置文件路径和覆盖项
+func parseFlags() {
+	overrides := setFlags{}
+	configPath := flag.String("config", "", "配置文件路径（默认在可执行文件目录或当前目录查找 config.json）")
+	host := flag.String("host", "", "监听地址，覆盖 server.host")
+	port := flag.Int("port", 0, "监听端口，覆盖 server.port")
+	pollInterval := flag.Int("poll-interval", 0, "默认推送间隔（毫秒），覆盖 server.pollInterval")
+	mode := flag.String("mode", "", "运行模式 standalone / agent / hub，覆盖 cluster.mode")
+	flag.Var(overrides, "set", "覆盖任意配置项，如 --set websocket.maxConnections=500，可重复")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags]\n       %s hash-password | gen-token | validate-config [file] | schema\n\nFlags:\n", os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	// 只有显式指定的参数才覆盖配置
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "host":
+			overrides["server.host"] = *host
+		case "port":
+			overrides["server.port"] = strconv.Itoa(*port)
+		case "poll-interval":
+			overrides["server.pollInterval"] = strconv.Itoa(*pollInterval)
+		case "mode":
+			overrides["cluster.mode"] = *mode
+		}
+	})
+
+	config.SetPath(*configPath)
+	config.SetOverrides(overrides)
+}
+
+// runCommand 执行辅助子命令
+func runCommand(name string, args []string) {
+	switch name {
+	case "validate-config":
+		// 校验配置文件，按行输出所有错误，配置有效时退出码为 0
+		var path string
+		if len(args) > 0 {
+			path = args[0]
+		}
+		path, err := config.ValidateFile(path)
+		var verr *config.ValidationError
+		switch {
+		case errors.As(err, &verr):
+			for _, fe := range verr.Errors {
+				fmt.Fprintf(os.Stderr, "%s:%d:%d: %s: %s\n", path, fe.Line, fe.Column, fe.Path, fe.Message)
+			}
+			os.Exit(1)
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s: OK\n", path)
+
+	case "schema":
+		// 输出配置文件的 JSON Schema
+		schema, err := config.Schema()
+		if err != nil {
+			log.Fatalf("Failed to generate schema: %v", err)
+		}
+		fmt.Println(string(schema))
+
+	case "hash-password":
+		// 从标准输入读取密码，输出用于 auth.users[].passwordHash 的 bcrypt 哈希
+		fmt.Fprint(os.Stderr, "Password: ")
+		password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && password == "" {
+			log.Fatalf("Failed to read password: %v", err)
+		}
+		hash, err := auth.HashPassword(strings.TrimRight(password, "\r\n"))
+		if err != nil {
+			log.Fatalf("Failed to hash password: %v", err)
+		}
+		fmt.Println(hash)
+
+	case "gen-token":
+		// 生成 API token，token 只显示一次，配置中保存其哈希
+		token, err := auth.GenerateToken()
+		if err != nil {
+			log.Fatalf("Failed to generate token: %v", err)
+		}
+		fmt.Printf("token:     %s\n", token)
+		fmt.Printf("tokenHash: %s\n", auth.HashToken(token))
+
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", name)
+		fmt.Fprintln(os.Stderr, "Available commands: hash-password, gen-token, validate-config, schema")
+		os.Exit(2)
+	}
 }
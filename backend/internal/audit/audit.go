@@ -0,0 +1,288 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/config"
+	"github.com/dat-G/MLServer_Dash/backend/internal/models"
+)
+
+// Logger 审计日志，追加写入 JSON Lines 文件，超过大小后轮转
+// 轮转后的文件依次命名为 audit.log.1、audit.log.2 ...，数字越大越旧
+type Logger struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// Query 查询条件，零值表示不过滤
+type Query struct {
+	Since  time.Time
+	User   string
+	Target string // 子串匹配，不区分大小写
+	Limit  int
+}
+
+var logger *Logger
+
+// Init 打开审计日志文件，失败时仅记录日志，审计功能不可用
+func Init(cfg config.AuditConfig) {
+	if !cfg.Enabled {
+		log.Println("Audit log disabled")
+		return
+	}
+
+	l := &Logger{
+		path:       cfg.Path,
+		maxSize:    int64(cfg.MaxSizeMB) * 1024 * 1024,
+		maxBackups: cfg.MaxBackups,
+	}
+	if l.path == "" {
+		l.path = "audit.log"
+	}
+	if l.maxSize <= 0 {
+		l.maxSize = 10 * 1024 * 1024
+	}
+	if l.maxBackups < 0 {
+		l.maxBackups = 0
+	}
+
+	if err := l.open(); err != nil {
+		log.Printf("Warning: Failed to open audit log %s: %v", l.path, err)
+		return
+	}
+	logger = l
+	log.Printf("Audit log: %s", l.path)
+}
+
+// Close 关闭审计日志文件
+func Close() {
+	if logger == nil {
+		return
+	}
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	if logger.file != nil {
+		logger.file.Close()
+		logger.file = nil
+	}
+}
+
+// Enabled 审计日志是否可用
+func Enabled() bool {
+	return logger != nil
+}
+
+// Record 写入一条审计记录
+func Record(entry models.AuditEntry) {
+	if logger == nil {
+		return
+	}
+	if err := logger.write(entry); err != nil {
+		log.Printf("Warning: Failed to write audit log: %v", err)
+	}
+}
+
+// Search 按条件查询审计记录，按时间倒序返回
+func Search(q Query) ([]models.AuditEntry, error) {
+	if logger == nil {
+		return nil, fmt.Errorf("audit log is not enabled")
+	}
+	return logger.search(q)
+}
+
+// open 以追加方式打开日志文件
+func (l *Logger) open() error {
+	if dir := filepath.Dir(l.path); dir != "." {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	l.file = f
+	l.size = info.Size()
+	return nil
+}
+
+// write 追加一行记录，必要时先轮转
+func (l *Logger) write(entry models.AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return fmt.Errorf("audit log is closed")
+	}
+	if l.size > 0 && l.size+int64(len(line)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	return err
+}
+
+// rotate 轮转日志文件，超出 maxBackups 的旧文件被删除
+func (l *Logger) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+	l.file = nil
+
+	if l.maxBackups == 0 {
+		if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return l.open()
+	}
+
+	os.Remove(l.backupPath(l.maxBackups))
+	for i := l.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(l.backupPath(i), l.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(l.path, l.backupPath(1)); err != nil {
+		return err
+	}
+	return l.open()
+}
+
+// backupPath 第 n 个轮转文件的路径
+func (l *Logger) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", l.path, n)
+}
+
+// segment 查询时打开的一个日志文件
+type segment struct {
+	file *os.File
+	size int64 // 只读取该长度，-1 表示读到文件末尾
+}
+
+// openSegments 从新到旧打开当前文件和轮转文件
+// 只在此处持有锁：已打开的文件在轮转（重命名或删除）后仍可读取，当前文件只读到打开时已写入的长度
+func (l *Logger) openSegments() ([]segment, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	segments := make([]segment, 0, l.maxBackups+1)
+	for i := 0; i <= l.maxBackups; i++ {
+		path, size := l.path, l.size
+		if i > 0 {
+			path, size = l.backupPath(i), -1
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			closeSegments(segments)
+			return nil, err
+		}
+		segments = append(segments, segment{file: f, size: size})
+	}
+	return segments, nil
+}
+
+func closeSegments(segments []segment) {
+	for _, s := range segments {
+		s.file.Close()
+	}
+}
+
+// search 从新到旧依次读取当前文件和轮转文件，找到 limit 条后不再读取更旧的文件
+func (l *Logger) search(q Query) ([]models.AuditEntry, error) {
+	segments, err := l.openSegments()
+	if err != nil {
+		return nil, err
+	}
+	defer closeSegments(segments)
+
+	target := strings.ToLower(q.Target)
+	entries := []models.AuditEntry{}
+	for _, seg := range segments {
+		var r io.Reader = seg.file
+		if seg.size >= 0 {
+			r = io.LimitReader(seg.file, seg.size)
+		}
+
+		// 文件内按写入顺序从旧到新，只保留最新的 limit 条
+		var matches []models.AuditEntry
+		var newest time.Time
+		if err := scanEntries(r, func(e models.AuditEntry) {
+			if e.Time.After(newest) {
+				newest = e.Time
+			}
+			if !q.Since.IsZero() && e.Time.Before(q.Since) {
+				return
+			}
+			if q.User != "" && e.User != q.User {
+				return
+			}
+			if target != "" && !strings.Contains(strings.ToLower(e.Target), target) {
+				return
+			}
+			matches = append(matches, e)
+			if q.Limit > 0 && len(matches) >= 2*q.Limit {
+				matches = append(matches[:0], matches[len(matches)-q.Limit:]...)
+			}
+		}); err != nil {
+			return nil, err
+		}
+
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].Time.After(matches[j].Time)
+		})
+		entries = append(entries, matches...)
+		if q.Limit > 0 && len(entries) >= q.Limit {
+			return entries[:q.Limit], nil
+		}
+		// 更旧的文件中不会有 since 之后的记录
+		if !q.Since.IsZero() && !newest.IsZero() && newest.Before(q.Since) {
+			break
+		}
+	}
+	return entries, nil
+}
+
+// scanEntries 逐行解析日志，无法解析的行被忽略
+func scanEntries(r io.Reader, fn func(models.AuditEntry)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e models.AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		fn(e)
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,171 @@
+package audit
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/models"
+)
+
+// newTestLogger 在临时目录中创建审计日志，maxSize 很小以便触发轮转
+func newTestLogger(t *testing.T, maxSize int64, maxBackups int) *Logger {
+	t.Helper()
+	l := &Logger{
+		path:       filepath.Join(t.TempDir(), "audit.log"),
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := l.open(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.file.Close() })
+	return l
+}
+
+// writeEntries 按时间顺序写入 n 条记录，第 i 条的 target 为 container:c<i>
+func writeEntries(t *testing.T, l *Logger, base time.Time, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		user := "alice"
+		if i%2 == 1 {
+			user = "bob"
+		}
+		err := l.write(models.AuditEntry{
+			Time:   base.Add(time.Duration(i) * time.Minute),
+			User:   user,
+			Action: "container.restart",
+			Target: fmt.Sprintf("container:c%d", i),
+			Status: 200,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func targets(entries []models.AuditEntry) []string {
+	result := make([]string, len(entries))
+	for i, e := range entries {
+		result[i] = e.Target
+	}
+	return result
+}
+
+func TestSearchNewestFirstAcrossRotation(t *testing.T) {
+	// 每条记录约 100 字节，每个文件只能放几条
+	l := newTestLogger(t, 400, 10)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	writeEntries(t, l, base, 20)
+
+	all, err := l.search(Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 20 {
+		t.Fatalf("search() returned %d entries, want 20", len(all))
+	}
+	for i, e := range all {
+		if want := fmt.Sprintf("container:c%d", 19-i); e.Target != want {
+			t.Fatalf("entry %d = %s, want %s (all: %v)", i, e.Target, want, targets(all))
+		}
+	}
+
+	limited, err := l.search(Query{Limit: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := targets(limited); fmt.Sprint(got) != "[container:c19 container:c18 container:c17]" {
+		t.Errorf("search(limit=3) = %v", got)
+	}
+}
+
+func TestSearchFilters(t *testing.T) {
+	l := newTestLogger(t, 400, 10)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	writeEntries(t, l, base, 20)
+
+	tests := []struct {
+		name  string
+		query Query
+		want  string
+	}{
+		{"since", Query{Since: base.Add(16 * time.Minute)}, "[container:c19 container:c18 container:c17 container:c16]"},
+		{"user", Query{User: "bob", Limit: 3}, "[container:c19 container:c17 container:c15]"},
+		{"target", Query{Target: "C1"}, "[container:c19 container:c18 container:c17 container:c16 container:c15 container:c14 container:c13 container:c12 container:c11 container:c10 container:c1]"},
+		{"since and user", Query{Since: base.Add(15 * time.Minute), User: "alice"}, "[container:c18 container:c16]"},
+		{"no match", Query{Since: base.Add(time.Hour)}, "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := l.search(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := fmt.Sprint(targets(entries)); got != tt.want {
+				t.Errorf("search() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchDropsRotatedOutFiles(t *testing.T) {
+	l := newTestLogger(t, 400, 1)
+	writeEntries(t, l, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), 20)
+
+	entries, err := l.search(Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) == 0 || len(entries) >= 20 || entries[0].Target != "container:c19" {
+		t.Errorf("search() with one backup = %v", targets(entries))
+	}
+}
+
+func TestSearchConcurrentWithWrites(t *testing.T) {
+	l := newTestLogger(t, 2048, 3)
+	base := time.Now()
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(done)
+		for i := 0; i < 500; i++ {
+			if err := l.write(models.AuditEntry{Time: base.Add(time.Duration(i) * time.Millisecond), Action: "process.kill"}); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	// 查询与写入和轮转并发进行，返回的记录总是按时间倒序且完整可解析
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				entries, err := l.search(Query{Limit: 50})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				for j := 1; j < len(entries); j++ {
+					if entries[j].Time.After(entries[j-1].Time) {
+						t.Errorf("entries out of order at %d", j)
+						return
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,338 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/config"
+)
+
+// 认证方式
+const (
+	MethodSession = "session"
+	MethodToken   = "token"
+	MethodOIDC    = "oidc"
+	MethodCert    = "certificate"
+)
+
+var (
+	// ErrInvalidCredentials 用户名或密码错误
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	// ErrUnauthenticated 请求未携带有效凭据
+	ErrUnauthenticated = errors.New("authentication required")
+)
+
+// dummyHash 用户不存在时用于比较的哈希，使响应时间与密码错误一致
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("mlserver-dash"), bcrypt.DefaultCost)
+
+// Principal 已认证的调用方
+type Principal struct {
+	Username string `json:"username"`
+	Role     Role   `json:"role"`
+	Method   string `json:"method"`
+}
+
+// Session 登录会话
+type Session struct {
+	ID        string
+	Username  string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	Role      Role         // OIDC 会话登录时映射的角色；本地用户的角色从配置读取
+	OIDC      *oidcSession // 非 OIDC 登录时为 nil
+
+	refreshMu sync.Mutex // 串行化同一会话的令牌刷新，避免 refresh token 轮换时并发使用旧令牌
+}
+
+// Manager 管理本地用户、API Token 和登录会话
+type Manager struct {
+	mu       sync.RWMutex
+	cfg      config.AuthConfig
+	users    map[string]config.UserConfig
+	tokens   map[string]config.TokenConfig // 键为 token 哈希
+	sessions map[string]*Session
+	oidc     *oidcClient // 未启用 OIDC 时为 nil
+}
+
+// ManagerInstance 全局认证管理器，由 main.go 初始化
+var ManagerInstance *Manager
+
+// Init 初始化认证管理器
+func Init(cfg config.AuthConfig) {
+	m := &Manager{sessions: make(map[string]*Session)}
+	m.load(cfg)
+	ManagerInstance = m
+}
+
+// ApplyConfig 热加载认证配置，用户、token、策略和 OIDC 设置立即生效
+// 已登录的会话保留，已删除用户的会话失效；OIDC 配置变化时原有的 OIDC 会话失效
+// 返回认证配置是否发生变化
+func ApplyConfig(cfg config.AuthConfig) bool {
+	if ManagerInstance == nil {
+		return false
+	}
+	return ManagerInstance.load(cfg)
+}
+
+// Enabled 是否启用认证
+func Enabled() bool {
+	return ManagerInstance != nil && ManagerInstance.Enabled()
+}
+
+// load 载入用户和 token 配置，可重复调用，返回配置是否发生变化
+func (m *Manager) load(cfg config.AuthConfig) bool {
+	users := make(map[string]config.UserConfig, len(cfg.Users))
+	for _, u := range cfg.Users {
+		if _, ok := ParseRole(u.Role); !ok {
+			log.Printf("Auth: unknown role %q for user %s, falling back to viewer", u.Role, u.Username)
+		}
+		users[u.Username] = u
+	}
+	tokens := make(map[string]config.TokenConfig, len(cfg.Tokens))
+	for _, t := range cfg.Tokens {
+		tokens[strings.ToLower(t.TokenHash)] = t
+	}
+
+	var oidcClient *oidcClient
+	if cfg.OIDC.Enabled {
+		for group, role := range cfg.OIDC.GroupRoles {
+			if _, ok := ParseRole(role); !ok {
+				log.Printf("Auth: unknown role %q for OIDC group %s, falling back to viewer", role, group)
+			}
+		}
+		if cfg.OIDC.Issuer == "" || cfg.OIDC.ClientID == "" || cfg.OIDC.RedirectURL == "" {
+			log.Printf("Auth: OIDC requires issuer, clientId and redirectUrl, SSO login disabled")
+		} else {
+			oidcClient = newOIDCClient(cfg.OIDC)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	changed := !reflect.DeepEqual(m.cfg, cfg)
+	if oidcClient != nil && m.oidc != nil && reflect.DeepEqual(m.oidc.cfg, cfg.OIDC) {
+		// OIDC 配置未变化时沿用原客户端，保留已发现的 provider 和进行中的登录
+		oidcClient = m.oidc
+	}
+	if oidcClient != m.oidc {
+		for id, s := range m.sessions {
+			if s.OIDC != nil {
+				delete(m.sessions, id)
+			}
+		}
+	}
+	m.cfg = cfg
+	m.users = users
+	m.tokens = tokens
+	m.oidc = oidcClient
+	return changed
+}
+
+// Enabled 是否启用认证
+func (m *Manager) Enabled() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.cfg.Enabled
+}
+
+// CookieName 会话 cookie 名称
+func (m *Manager) CookieName() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.cfg.CookieName == "" {
+		return "mldash_session"
+	}
+	return m.cfg.CookieName
+}
+
+// SecureCookie 会话 cookie 是否只通过 HTTPS 发送
+func (m *Manager) SecureCookie() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.cfg.SecureCookie
+}
+
+// sessionTTL 会话有效期
+func (m *Manager) sessionTTL() time.Duration {
+	if m.cfg.SessionTTL <= 0 {
+		return 12 * time.Hour
+	}
+	return time.Duration(m.cfg.SessionTTL) * time.Minute
+}
+
+// Login 校验用户名密码并创建会话
+func (m *Manager) Login(username, password string) (*Session, error) {
+	m.mu.RLock()
+	user, ok := m.users[username]
+	ttl := m.sessionTTL()
+	m.mu.RUnlock()
+
+	hash := []byte(user.PasswordHash)
+	if !ok || user.PasswordHash == "" {
+		hash = dummyHash
+	}
+	if err := bcrypt.CompareHashAndPassword(hash, []byte(password)); err != nil || !ok {
+		return nil, ErrInvalidCredentials
+	}
+
+	return m.NewSession(username, ttl)
+}
+
+// NewSession 为用户创建会话
+func (m *Manager) NewSession(username string, ttl time.Duration) (*Session, error) {
+	id, err := randomString(32)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &Session{
+		ID:        id,
+		Username:  username,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[id] = session
+	m.pruneSessionsLocked(now)
+
+	return session, nil
+}
+
+// Logout 删除会话
+func (m *Manager) Logout(sessionID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, sessionID)
+}
+
+// pruneSessionsLocked 清理过期会话，调用方需持有写锁
+func (m *Manager) pruneSessionsLocked(now time.Time) {
+	for id, s := range m.sessions {
+		if now.After(s.ExpiresAt) {
+			delete(m.sessions, id)
+		}
+	}
+}
+
+// session 查询有效会话
+func (m *Manager) session(id string) (*Session, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	s, ok := m.sessions[id]
+	if !ok || time.Now().After(s.ExpiresAt) {
+		return nil, false
+	}
+	if s.OIDC == nil {
+		if _, exists := m.users[s.Username]; !exists {
+			// 用户已从配置中删除
+			return nil, false
+		}
+	} else if m.oidc == nil {
+		// OIDC 已被关闭
+		return nil, false
+	}
+	return s, true
+}
+
+// oidcSession 返回会话当前的 OIDC 令牌，refreshOIDC 会在持有写锁时替换它
+func (m *Manager) oidcSession(s *Session) *oidcSession {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return s.OIDC
+}
+
+// Authenticate 从请求中识别调用方
+// 依次尝试 Authorization: Bearer <token>、会话 cookie 和已校验的 TLS 客户端证书
+func (m *Manager) Authenticate(r *http.Request) (*Principal, error) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") {
+			return nil, ErrUnauthenticated
+		}
+		return m.authenticateToken(strings.TrimSpace(token))
+	}
+
+	if cookie, err := r.Cookie(m.CookieName()); err == nil && cookie.Value != "" {
+		if s, ok := m.session(cookie.Value); ok {
+			if m.oidcSession(s) == nil {
+				return &Principal{Username: s.Username, Role: m.UserRole(s.Username), Method: MethodSession}, nil
+			}
+			// OIDC 会话在 sessionTtl 内有效，与 provider 令牌的有效期无关，令牌只在 /api/auth/refresh 时刷新
+			m.mu.RLock()
+			role := s.Role
+			m.mu.RUnlock()
+			return &Principal{Username: s.Username, Role: role, Method: MethodOIDC}, nil
+		}
+	}
+
+	if p := m.authenticateCert(r); p != nil {
+		return p, nil
+	}
+
+	return nil, ErrUnauthenticated
+}
+
+// authenticateCert 使用 TLS 客户端证书认证，证书的 CN 必须是已配置的用户
+// 只有经过 server.tls.clientCaFile 校验的证书才会被接受
+func (m *Manager) authenticateCert(r *http.Request) *Principal {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	cn := r.TLS.VerifiedChains[0][0].Subject.CommonName
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	user, ok := m.users[cn]
+	if !ok {
+		return nil
+	}
+	role, _ := ParseRole(user.Role)
+	return &Principal{Username: cn, Role: role, Method: MethodCert}
+}
+
+// authenticateToken 校验 API token
+func (m *Manager) authenticateToken(token string) (*Principal, error) {
+	if token == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	t, ok := m.tokens[HashToken(token)]
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	user, exists := m.users[t.User]
+	if !exists {
+		return nil, ErrUnauthenticated
+	}
+	role, _ := ParseRole(user.Role)
+	return &Principal{Username: t.User, Role: role, Method: MethodToken}, nil
+}
+
+// HasLocalUsers 是否配置了本地用户
+func (m *Manager) HasLocalUsers() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.users) > 0
+}
+
+// UserRole 查询用户角色
+func (m *Manager) UserRole(username string) Role {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	role, _ := ParseRole(m.users[username].Role)
+	return role
+}
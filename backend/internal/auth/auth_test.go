@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/config"
+)
+
+func authenticateToken(m *Manager, token string) (*Principal, error) {
+	r := httptest.NewRequest(http.MethodGet, "/api/system", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	return m.Authenticate(r)
+}
+
+func TestReloadLocalUsers(t *testing.T) {
+	const token = "test-token"
+	cfg := config.AuthConfig{
+		Enabled: true,
+		Users: []config.UserConfig{
+			{Username: "alice", Role: "admin"},
+			{Username: "bob", Role: "operator"},
+		},
+		Tokens: []config.TokenConfig{{User: "bob", TokenHash: HashToken(token)}},
+	}
+	m := &Manager{sessions: make(map[string]*Session)}
+	m.load(cfg)
+
+	alice, err := m.NewSession("alice", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := m.NewSession("bob", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.load(cfg) {
+		t.Error("load() with an unchanged config = true")
+	}
+
+	// 删除 alice、bob 降为 viewer：alice 的会话失效，bob 的会话和 token 按新角色认证
+	cfg.Users = []config.UserConfig{{Username: "bob", Role: "viewer"}}
+	if !m.load(cfg) {
+		t.Error("load() after changing users = false")
+	}
+
+	if _, err := authenticate(m, alice.ID); err != ErrUnauthenticated {
+		t.Errorf("session of removed user: err = %v, want ErrUnauthenticated", err)
+	}
+	if p, err := authenticate(m, bob.ID); err != nil || p.Role != RoleViewer {
+		t.Errorf("session of bob = %+v, %v, want viewer", p, err)
+	}
+	if p, err := authenticateToken(m, token); err != nil || p.Role != RoleViewer {
+		t.Errorf("token of bob = %+v, %v, want viewer", p, err)
+	}
+
+	// 删除 token 后立即失效
+	cfg.Tokens = nil
+	m.load(cfg)
+	if _, err := authenticateToken(m, token); err != ErrUnauthenticated {
+		t.Errorf("removed token: err = %v, want ErrUnauthenticated", err)
+	}
+
+	// 关闭认证后由中间件放行，会话本身保留
+	cfg.Enabled = false
+	m.load(cfg)
+	if m.Enabled() {
+		t.Error("Enabled() after disabling = true")
+	}
+	if _, ok := m.session(bob.ID); !ok {
+		t.Error("session of bob dropped after disabling auth")
+	}
+}
+
+func TestReloadOIDC(t *testing.T) {
+	issuer := newMockIssuer(t)
+	m := newOIDCManager(t, issuer, "viewer")
+	s, err := login(t, m, issuer, "carol")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// OIDC 配置未变化（如只修改了本地用户）时沿用原客户端，会话保留
+	cfg := m.cfg
+	client := m.oidc
+	cfg.Users = []config.UserConfig{{Username: "alice", Role: "admin"}}
+	m.load(cfg)
+	if m.oidc != client {
+		t.Error("OIDC client replaced although the OIDC config did not change")
+	}
+	if _, err := authenticate(m, s.ID); err != nil {
+		t.Errorf("OIDC session after unrelated reload: %v", err)
+	}
+
+	// OIDC 配置变化后原有的 OIDC 会话失效
+	cfg.OIDC.ClientID = "other-client"
+	m.load(cfg)
+	if _, err := authenticate(m, s.ID); err != ErrUnauthenticated {
+		t.Errorf("OIDC session after OIDC change: err = %v, want ErrUnauthenticated", err)
+	}
+
+	// 关闭 OIDC
+	cfg.OIDC.Enabled = false
+	m.load(cfg)
+	if m.OIDCEnabled() {
+		t.Error("OIDCEnabled() after disabling = true")
+	}
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// tokenPrefix API token 前缀，便于在日志和密钥扫描中识别
+const tokenPrefix = "mldash_"
+
+// HashPassword 使用 bcrypt 生成密码哈希
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// HashToken 计算 API token 的 SHA-256 哈希（十六进制）
+// token 为高熵随机串，无需使用慢哈希
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateToken 生成新的 API token
+func GenerateToken() (string, error) {
+	s, err := randomString(32)
+	if err != nil {
+		return "", err
+	}
+	return tokenPrefix + s, nil
+}
+
+// randomString 生成 n 字节随机数的 base64url 编码
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
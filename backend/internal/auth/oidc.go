@@ -0,0 +1,452 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/config"
+)
+
+// pendingLoginTTL 授权请求的有效期
+const pendingLoginTTL = 10 * time.Minute
+
+// oidcTimeout 与身份提供方通信的超时时间
+const oidcTimeout = 10 * time.Second
+
+var (
+	// ErrOIDCDisabled 未配置 OIDC
+	ErrOIDCDisabled = errors.New("OIDC login is not enabled")
+	// ErrInvalidState state 无效或已过期
+	ErrInvalidState = errors.New("invalid or expired login state")
+	// ErrNoRole 用户所属的组没有映射到任何角色
+	ErrNoRole = errors.New("user is not a member of any group mapped to a dashboard role")
+	// ErrRefreshFailed 无法向 provider 刷新令牌，会话本身仍然有效
+	ErrRefreshFailed = errors.New("OIDC token refresh failed")
+)
+
+// pendingLogin 等待回调的授权请求
+type pendingLogin struct {
+	verifier  string // PKCE code_verifier
+	nonce     string
+	expiresAt time.Time
+}
+
+// oidcSession OIDC 会话附带的令牌，用于刷新和注销
+type oidcSession struct {
+	token   *oauth2.Token
+	idToken string
+}
+
+// oidcClient OIDC 授权码 + PKCE 登录
+type oidcClient struct {
+	mu       sync.Mutex
+	cfg      config.OIDCConfig
+	provider *oidc.Provider
+	verifier *oidc.IDTokenVerifier
+	oauth    *oauth2.Config
+	logout   string // end_session_endpoint
+	pending  map[string]pendingLogin
+}
+
+// oidcClaims 从 ID token 中读取的声明
+type oidcClaims struct {
+	username string
+	groups   []string
+}
+
+// newOIDCClient 创建 OIDC 客户端，provider 在首次使用时发现
+func newOIDCClient(cfg config.OIDCConfig) *oidcClient {
+	return &oidcClient{
+		cfg:     cfg,
+		pending: make(map[string]pendingLogin),
+	}
+}
+
+// discover 获取 provider 元数据（/.well-known/openid-configuration），失败后下次重试
+func (o *oidcClient) discover(ctx context.Context) error {
+	if o.provider != nil {
+		return nil
+	}
+
+	provider, err := oidc.NewProvider(ctx, o.cfg.Issuer)
+	if err != nil {
+		return fmt.Errorf("OIDC discovery failed: %w", err)
+	}
+
+	scopes := o.cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{oidc.ScopeOpenID, "profile", "email", "groups"}
+	}
+
+	var metadata struct {
+		EndSessionEndpoint string `json:"end_session_endpoint"`
+	}
+	if err := provider.Claims(&metadata); err != nil {
+		log.Printf("OIDC: failed to read provider metadata: %v", err)
+	}
+
+	o.provider = provider
+	o.verifier = provider.Verifier(&oidc.Config{ClientID: o.cfg.ClientID})
+	o.logout = metadata.EndSessionEndpoint
+	o.oauth = &oauth2.Config{
+		ClientID:     o.cfg.ClientID,
+		ClientSecret: o.cfg.ClientSecret,
+		Endpoint:     provider.Endpoint(),
+		RedirectURL:  o.cfg.RedirectURL,
+		Scopes:       scopes,
+	}
+	return nil
+}
+
+// AuthCodeURL 生成授权地址，并保存 state、nonce 和 PKCE verifier
+func (o *oidcClient) AuthCodeURL(ctx context.Context) (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if err := o.discover(ctx); err != nil {
+		return "", err
+	}
+
+	state, err := randomString(24)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := randomString(24)
+	if err != nil {
+		return "", err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	now := time.Now()
+	for s, p := range o.pending {
+		if now.After(p.expiresAt) {
+			delete(o.pending, s)
+		}
+	}
+	o.pending[state] = pendingLogin{
+		verifier:  verifier,
+		nonce:     nonce,
+		expiresAt: now.Add(pendingLoginTTL),
+	}
+
+	return o.oauth.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)), nil
+}
+
+// Exchange 用授权码换取令牌并校验 ID token
+func (o *oidcClient) Exchange(ctx context.Context, state, code string) (*oauth2.Token, string, oidcClaims, error) {
+	o.mu.Lock()
+	pending, ok := o.pending[state]
+	delete(o.pending, state)
+	oauthCfg := o.oauth
+	o.mu.Unlock()
+
+	if !ok || time.Now().After(pending.expiresAt) || oauthCfg == nil {
+		return nil, "", oidcClaims{}, ErrInvalidState
+	}
+
+	token, err := oauthCfg.Exchange(ctx, code, oauth2.VerifierOption(pending.verifier))
+	if err != nil {
+		return nil, "", oidcClaims{}, fmt.Errorf("code exchange failed: %w", err)
+	}
+
+	rawIDToken, claims, err := o.verifyToken(ctx, token, pending.nonce)
+	if err != nil {
+		return nil, "", oidcClaims{}, err
+	}
+	return token, rawIDToken, claims, nil
+}
+
+// Refresh 使用 refresh token 刷新令牌，并重新读取用户组
+func (o *oidcClient) Refresh(ctx context.Context, s *oidcSession) (*oauth2.Token, string, oidcClaims, error) {
+	o.mu.Lock()
+	oauthCfg := o.oauth
+	o.mu.Unlock()
+
+	if oauthCfg == nil || s.token.RefreshToken == "" {
+		return nil, "", oidcClaims{}, errors.New("session cannot be refreshed")
+	}
+
+	// 将 Expiry 置零以强制刷新
+	expired := *s.token
+	expired.Expiry = time.Now().Add(-time.Minute)
+	token, err := oauthCfg.TokenSource(ctx, &expired).Token()
+	if err != nil {
+		return nil, "", oidcClaims{}, fmt.Errorf("token refresh failed: %w", err)
+	}
+
+	// 部分 provider 刷新时不返回新的 ID token，此时沿用原有声明
+	if _, ok := token.Extra("id_token").(string); !ok {
+		return token, s.idToken, oidcClaims{}, nil
+	}
+	rawIDToken, claims, err := o.verifyToken(ctx, token, "")
+	if err != nil {
+		return nil, "", oidcClaims{}, err
+	}
+	return token, rawIDToken, claims, nil
+}
+
+// verifyToken 校验 ID token 的签名、audience、过期时间和 nonce，并提取用户名和组
+func (o *oidcClient) verifyToken(ctx context.Context, token *oauth2.Token, nonce string) (string, oidcClaims, error) {
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return "", oidcClaims{}, errors.New("token response has no id_token")
+	}
+
+	idToken, err := o.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return "", oidcClaims{}, fmt.Errorf("invalid id_token: %w", err)
+	}
+	if nonce != "" && idToken.Nonce != nonce {
+		return "", oidcClaims{}, errors.New("id_token nonce mismatch")
+	}
+
+	var raw map[string]interface{}
+	if err := idToken.Claims(&raw); err != nil {
+		return "", oidcClaims{}, err
+	}
+
+	var claims oidcClaims
+	usernameClaim := o.cfg.UsernameClaim
+	if usernameClaim == "" {
+		usernameClaim = "preferred_username"
+	}
+	if name, ok := raw[usernameClaim].(string); ok && name != "" {
+		claims.username = name
+	} else {
+		claims.username = idToken.Subject
+	}
+
+	groupsClaim := o.cfg.GroupsClaim
+	if groupsClaim == "" {
+		groupsClaim = "groups"
+	}
+	switch groups := raw[groupsClaim].(type) {
+	case []interface{}:
+		for _, g := range groups {
+			if s, ok := g.(string); ok {
+				claims.groups = append(claims.groups, s)
+			}
+		}
+	case string:
+		claims.groups = []string{groups}
+	}
+
+	return rawIDToken, claims, nil
+}
+
+// mapRole 将用户组映射为角色，取最高的角色；都不匹配时使用 defaultRole
+func (o *oidcClient) mapRole(groups []string) (Role, error) {
+	best, found := RoleViewer, false
+	for _, g := range groups {
+		name, ok := o.cfg.GroupRoles[g]
+		if !ok {
+			continue
+		}
+		role, _ := ParseRole(name)
+		if !found || role > best {
+			best, found = role, true
+		}
+	}
+	if found {
+		return best, nil
+	}
+	if o.cfg.DefaultRole != "" {
+		role, _ := ParseRole(o.cfg.DefaultRole)
+		return role, nil
+	}
+	return RoleViewer, ErrNoRole
+}
+
+// LogoutURL 返回 provider 的注销地址（RP-Initiated Logout），不支持时返回空字符串
+func (o *oidcClient) LogoutURL(s *oidcSession) string {
+	o.mu.Lock()
+	endpoint := o.logout
+	o.mu.Unlock()
+
+	if endpoint == "" {
+		return ""
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return ""
+	}
+	q := u.Query()
+	if s != nil && s.idToken != "" {
+		q.Set("id_token_hint", s.idToken)
+	}
+	if o.cfg.PostLogoutRedirectURL != "" {
+		q.Set("post_logout_redirect_uri", o.cfg.PostLogoutRedirectURL)
+	}
+	q.Set("client_id", o.cfg.ClientID)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// OIDCEnabled 是否启用 OIDC 登录
+func (m *Manager) OIDCEnabled() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.cfg.Enabled && m.oidc != nil
+}
+
+// OIDCProviderName OIDC 登录按钮显示的名称
+func (m *Manager) OIDCProviderName() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.oidc == nil || m.oidc.cfg.ProviderName == "" {
+		return "SSO"
+	}
+	return m.oidc.cfg.ProviderName
+}
+
+// OIDCLoginURL 开始 OIDC 登录，返回需要重定向到的授权地址
+func (m *Manager) OIDCLoginURL(ctx context.Context) (string, error) {
+	m.mu.RLock()
+	client := m.oidc
+	m.mu.RUnlock()
+	if client == nil {
+		return "", ErrOIDCDisabled
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, oidcTimeout)
+	defer cancel()
+	return client.AuthCodeURL(ctx)
+}
+
+// OIDCCallback 处理授权回调，校验通过后创建会话
+func (m *Manager) OIDCCallback(ctx context.Context, state, code string) (*Session, error) {
+	m.mu.RLock()
+	client := m.oidc
+	ttl := m.sessionTTL()
+	m.mu.RUnlock()
+	if client == nil {
+		return nil, ErrOIDCDisabled
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, oidcTimeout)
+	defer cancel()
+
+	token, rawIDToken, claims, err := client.Exchange(ctx, state, code)
+	if err != nil {
+		return nil, err
+	}
+	role, err := client.mapRole(claims.groups)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := m.NewSession(claims.username, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	session.Role = role
+	session.OIDC = &oidcSession{token: token, idToken: rawIDToken}
+	m.mu.Unlock()
+
+	log.Printf("OIDC: user %s logged in with role %s", claims.username, role)
+	return session, nil
+}
+
+// RefreshSession 延长会话有效期；OIDC 会话同时刷新令牌并重新映射角色
+// provider 拒绝刷新或会话没有 refresh token 时返回 ErrRefreshFailed，会话不延长但在原有效期内仍然有效；
+// 用户已不属于任何映射到角色的组时会话失效。返回刷新后会话的副本
+func (m *Manager) RefreshSession(ctx context.Context, sessionID string) (*Session, error) {
+	s, ok := m.session(sessionID)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	m.mu.RLock()
+	client := m.oidc
+	ttl := m.sessionTTL()
+	m.mu.RUnlock()
+
+	if m.oidcSession(s) != nil {
+		if err := m.refreshOIDC(ctx, client, s); err != nil {
+			if errors.Is(err, ErrNoRole) {
+				log.Printf("OIDC: session of %s invalidated: %v", s.Username, err)
+				m.Logout(sessionID)
+				return nil, err
+			}
+			log.Printf("OIDC: session of %s not refreshed: %v", s.Username, err)
+			return nil, fmt.Errorf("%w: %v", ErrRefreshFailed, err)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s.ExpiresAt = time.Now().Add(ttl)
+	return &Session{
+		ID:        s.ID,
+		Username:  s.Username,
+		CreatedAt: s.CreatedAt,
+		ExpiresAt: s.ExpiresAt,
+		Role:      s.Role,
+		OIDC:      s.OIDC,
+	}, nil
+}
+
+// refreshOIDC 使用 refresh token 刷新 OIDC 会话
+// 同一会话的刷新串行进行，不同会话互不阻塞
+func (m *Manager) refreshOIDC(ctx context.Context, client *oidcClient, s *Session) error {
+	if client == nil {
+		return ErrOIDCDisabled
+	}
+
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	m.mu.RLock()
+	current := *s.OIDC
+	m.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, oidcTimeout)
+	defer cancel()
+
+	token, rawIDToken, claims, err := client.Refresh(ctx, &current)
+	if err != nil {
+		return err
+	}
+
+	var role Role
+	if claims.username != "" {
+		if role, err = client.mapRole(claims.groups); err != nil {
+			return err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s.OIDC = &oidcSession{token: token, idToken: rawIDToken}
+	if claims.username != "" {
+		s.Role = role
+	}
+	return nil
+}
+
+// LogoutURL 删除会话并返回 provider 的注销地址（如有）
+func (m *Manager) LogoutURL(sessionID string) string {
+	m.mu.Lock()
+	var tokens *oidcSession
+	if s, ok := m.sessions[sessionID]; ok {
+		tokens = s.OIDC
+	}
+	delete(m.sessions, sessionID)
+	client := m.oidc
+	m.mu.Unlock()
+
+	if tokens == nil || client == nil {
+		return ""
+	}
+	return client.LogoutURL(tokens)
+}
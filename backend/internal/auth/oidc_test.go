@@ -0,0 +1,556 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/config"
+)
+
+const (
+	testClientID = "mldash"
+	testKeyID    = "test-key"
+)
+
+// mockIssuer 进程内的 OIDC provider：discovery、JWKS、token 和注销端点
+// 授权端点不经过浏览器，测试通过 authorize 直接签发授权码
+type mockIssuer struct {
+	srv *httptest.Server
+	key *rsa.PrivateKey
+
+	mu            sync.Mutex
+	codes         map[string]issuedCode
+	refresh       map[string]string   // refresh token -> 用户
+	groups        map[string][]string // 用户 -> 组
+	nonce         string              // 非空时签发的 ID token 使用该 nonce（模拟重放）
+	noIDOnRefresh bool                // 刷新时不返回新的 ID token
+	refreshes     int
+}
+
+// issuedCode 已签发的授权码
+type issuedCode struct {
+	user      string
+	nonce     string
+	challenge string
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &mockIssuer{
+		key:     key,
+		codes:   make(map[string]issuedCode),
+		refresh: make(map[string]string),
+		groups:  make(map[string][]string),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", m.handleDiscovery)
+	mux.HandleFunc("/jwks", m.handleJWKS)
+	mux.HandleFunc("/token", m.handleToken)
+	m.srv = httptest.NewServer(mux)
+	t.Cleanup(m.srv.Close)
+	return m
+}
+
+func (m *mockIssuer) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                m.srv.URL,
+		"authorization_endpoint":                m.srv.URL + "/authorize",
+		"token_endpoint":                        m.srv.URL + "/token",
+		"jwks_uri":                              m.srv.URL + "/jwks",
+		"end_session_endpoint":                  m.srv.URL + "/logout",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (m *mockIssuer) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	pub := m.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": testKeyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+func (m *mockIssuer) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var user, nonce string
+	includeIDToken := true
+	switch r.Form.Get("grant_type") {
+	case "authorization_code":
+		code, ok := m.codes[r.Form.Get("code")]
+		delete(m.codes, r.Form.Get("code"))
+		if !ok || s256(r.Form.Get("code_verifier")) != code.challenge {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		user, nonce = code.user, code.nonce
+	case "refresh_token":
+		var ok bool
+		user, ok = m.refresh[r.Form.Get("refresh_token")]
+		delete(m.refresh, r.Form.Get("refresh_token")) // 轮换 refresh token
+		if !ok {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		m.refreshes++
+		includeIDToken = !m.noIDOnRefresh
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+	if m.nonce != "" {
+		nonce = m.nonce
+	}
+
+	refreshToken := randomTestString()
+	m.refresh[refreshToken] = user
+	resp := map[string]interface{}{
+		"access_token":  randomTestString(),
+		"token_type":    "Bearer",
+		"expires_in":    3600,
+		"refresh_token": refreshToken,
+	}
+	if includeIDToken {
+		resp["id_token"] = m.signIDToken(user, nonce, m.groups[user])
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// signIDToken 签发 RS256 ID token
+func (m *mockIssuer) signIDToken(user, nonce string, groups []string) string {
+	now := time.Now()
+	claims := map[string]interface{}{
+		"iss":                m.srv.URL,
+		"sub":                "sub-" + user,
+		"aud":                testClientID,
+		"iat":                now.Unix(),
+		"exp":                now.Add(time.Hour).Unix(),
+		"preferred_username": user,
+		"groups":             groups,
+	}
+	if nonce != "" {
+		claims["nonce"] = nonce
+	}
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": testKeyID, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, m.key, crypto.SHA256, digest[:])
+	if err != nil {
+		panic(err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// authorize 模拟用户在 provider 登录并同意授权，返回回调中的 state 和 code
+func (m *mockIssuer) authorize(t *testing.T, authURL, user string) (state, code string) {
+	t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if u.Path != "/authorize" || q.Get("client_id") != testClientID || q.Get("response_type") != "code" {
+		t.Fatalf("unexpected authorization URL %s", authURL)
+	}
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		t.Fatalf("authorization URL has no PKCE challenge: %s", authURL)
+	}
+	if q.Get("nonce") == "" || q.Get("state") == "" {
+		t.Fatalf("authorization URL has no state or nonce: %s", authURL)
+	}
+
+	code = randomTestString()
+	m.mu.Lock()
+	m.codes[code] = issuedCode{user: user, nonce: q.Get("nonce"), challenge: q.Get("code_challenge")}
+	m.mu.Unlock()
+	return q.Get("state"), code
+}
+
+func (m *mockIssuer) setGroups(user string, groups ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.groups[user] = groups
+}
+
+func (m *mockIssuer) revokeRefreshTokens() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.refresh = make(map[string]string)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func s256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomTestString() string {
+	s, err := randomString(16)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// newOIDCManager 创建使用 mock issuer 的认证管理器
+func newOIDCManager(t *testing.T, issuer *mockIssuer, defaultRole string) *Manager {
+	t.Helper()
+	m := &Manager{sessions: make(map[string]*Session)}
+	m.load(config.AuthConfig{
+		Enabled: true,
+		OIDC: config.OIDCConfig{
+			Enabled:               true,
+			Issuer:                issuer.srv.URL,
+			ClientID:              testClientID,
+			RedirectURL:           "https://dash.example.com/api/auth/oidc/callback",
+			PostLogoutRedirectURL: "https://dash.example.com/",
+			GroupRoles:            map[string]string{"ml-admins": "admin", "ml-users": "operator"},
+			DefaultRole:           defaultRole,
+		},
+	})
+	return m
+}
+
+// login 完成一次授权码登录
+func login(t *testing.T, m *Manager, issuer *mockIssuer, user string) (*Session, error) {
+	t.Helper()
+	authURL, err := m.OIDCLoginURL(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, code := issuer.authorize(t, authURL, user)
+	return m.OIDCCallback(context.Background(), state, code)
+}
+
+// authenticate 使用会话 cookie 认证
+func authenticate(m *Manager, sessionID string) (*Principal, error) {
+	r := httptest.NewRequest(http.MethodGet, "/api/system", nil)
+	r.AddCookie(&http.Cookie{Name: m.CookieName(), Value: sessionID})
+	return m.Authenticate(r)
+}
+
+func TestOIDCLoginWithPKCE(t *testing.T) {
+	issuer := newMockIssuer(t)
+	issuer.setGroups("alice", "ml-users")
+	m := newOIDCManager(t, issuer, "")
+
+	session, err := login(t, m, issuer, "alice")
+	if err != nil {
+		t.Fatalf("OIDCCallback() error = %v", err)
+	}
+	if session.Username != "alice" || session.Role != RoleOperator {
+		t.Errorf("session = %s/%s, want alice/operator", session.Username, session.Role)
+	}
+
+	p, err := authenticate(m, session.ID)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if p.Username != "alice" || p.Role != RoleOperator || p.Method != MethodOIDC {
+		t.Errorf("principal = %+v, want alice/operator/oidc", p)
+	}
+}
+
+func TestOIDCStateIsSingleUse(t *testing.T) {
+	issuer := newMockIssuer(t)
+	issuer.setGroups("alice", "ml-users")
+	m := newOIDCManager(t, issuer, "")
+
+	authURL, err := m.OIDCLoginURL(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, code := issuer.authorize(t, authURL, "alice")
+	if _, err := m.OIDCCallback(context.Background(), state, code); err != nil {
+		t.Fatalf("first callback error = %v", err)
+	}
+	if _, err := m.OIDCCallback(context.Background(), state, code); !errors.Is(err, ErrInvalidState) {
+		t.Errorf("replayed callback error = %v, want ErrInvalidState", err)
+	}
+	if _, err := m.OIDCCallback(context.Background(), "forged", code); !errors.Is(err, ErrInvalidState) {
+		t.Errorf("unknown state error = %v, want ErrInvalidState", err)
+	}
+}
+
+func TestOIDCRejectsWrongVerifier(t *testing.T) {
+	issuer := newMockIssuer(t)
+	issuer.setGroups("alice", "ml-users")
+	m := newOIDCManager(t, issuer, "")
+
+	// 攻击者截获的授权码属于另一次登录（不同的 code_challenge），本次登录的 verifier 无法兑换
+	stolenURL, err := m.OIDCLoginURL(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, stolenCode := issuer.authorize(t, stolenURL, "alice")
+
+	authURL, err := m.OIDCLoginURL(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, _ := issuer.authorize(t, authURL, "alice")
+
+	_, err = m.OIDCCallback(context.Background(), state, stolenCode)
+	if err == nil || !strings.Contains(err.Error(), "code exchange failed") {
+		t.Errorf("callback with mismatched verifier error = %v, want code exchange failure", err)
+	}
+}
+
+func TestOIDCNonceMismatch(t *testing.T) {
+	issuer := newMockIssuer(t)
+	issuer.setGroups("alice", "ml-users")
+	issuer.nonce = "replayed-nonce"
+	m := newOIDCManager(t, issuer, "")
+
+	_, err := login(t, m, issuer, "alice")
+	if err == nil || !strings.Contains(err.Error(), "nonce mismatch") {
+		t.Errorf("OIDCCallback() error = %v, want nonce mismatch", err)
+	}
+}
+
+func TestOIDCGroupRoles(t *testing.T) {
+	tests := []struct {
+		name        string
+		groups      []string
+		defaultRole string
+		want        Role
+		wantErr     error
+	}{
+		{name: "operator group", groups: []string{"ml-users"}, want: RoleOperator},
+		{name: "highest role wins", groups: []string{"students", "ml-users", "ml-admins"}, want: RoleAdmin},
+		{name: "no mapped group", groups: []string{"students"}, wantErr: ErrNoRole},
+		{name: "no groups", wantErr: ErrNoRole},
+		{name: "default role", groups: []string{"students"}, defaultRole: "viewer", want: RoleViewer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := newMockIssuer(t)
+			issuer.setGroups("alice", tt.groups...)
+			m := newOIDCManager(t, issuer, tt.defaultRole)
+
+			session, err := login(t, m, issuer, "alice")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("OIDCCallback() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("OIDCCallback() error = %v", err)
+			}
+			if session.Role != tt.want {
+				t.Errorf("role = %s, want %s", session.Role, tt.want)
+			}
+		})
+	}
+}
+
+func TestOIDCRefreshRemapsRole(t *testing.T) {
+	issuer := newMockIssuer(t)
+	issuer.setGroups("alice", "ml-users")
+	m := newOIDCManager(t, issuer, "")
+
+	session, err := login(t, m, issuer, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 用户在 provider 中被加入管理员组，刷新后角色随之变化
+	issuer.setGroups("alice", "ml-admins")
+	refreshed, err := m.RefreshSession(context.Background(), session.ID)
+	if err != nil {
+		t.Fatalf("RefreshSession() error = %v", err)
+	}
+	if refreshed.Role != RoleAdmin {
+		t.Errorf("role after refresh = %s, want admin", refreshed.Role)
+	}
+
+	// provider 不返回新的 ID token 时沿用原有角色
+	issuer.mu.Lock()
+	issuer.noIDOnRefresh = true
+	issuer.mu.Unlock()
+	issuer.setGroups("alice")
+	if refreshed, err = m.RefreshSession(context.Background(), session.ID); err != nil {
+		t.Fatalf("RefreshSession() without id_token error = %v", err)
+	}
+	if refreshed.Role != RoleAdmin {
+		t.Errorf("role after refresh without id_token = %s, want admin", refreshed.Role)
+	}
+
+	// 被移出所有组后刷新失败，会话失效
+	issuer.mu.Lock()
+	issuer.noIDOnRefresh = false
+	issuer.mu.Unlock()
+	if _, err := m.RefreshSession(context.Background(), session.ID); !errors.Is(err, ErrNoRole) {
+		t.Fatalf("RefreshSession() after group removal error = %v, want ErrNoRole", err)
+	}
+	if _, err := authenticate(m, session.ID); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Authenticate() after failed refresh error = %v, want ErrUnauthenticated", err)
+	}
+}
+
+// expireToken 将会话的 provider 令牌标记为已过期
+func expireToken(m *Manager, sessionID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.sessions[sessionID]
+	token := *s.OIDC.token
+	token.Expiry = time.Now().Add(-time.Minute)
+	s.OIDC = &oidcSession{token: &token, idToken: s.OIDC.idToken}
+}
+
+func TestOIDCSessionOutlivesProviderToken(t *testing.T) {
+	issuer := newMockIssuer(t)
+	issuer.setGroups("alice", "ml-users")
+	m := newOIDCManager(t, issuer, "")
+
+	session, err := login(t, m, issuer, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiresAt := session.ExpiresAt
+
+	// provider 令牌过期不影响会话，请求认证时也不刷新令牌
+	expireToken(m, session.ID)
+	if _, err := authenticate(m, session.ID); err != nil {
+		t.Fatalf("Authenticate() with expired token error = %v", err)
+	}
+	issuer.mu.Lock()
+	refreshes := issuer.refreshes
+	issuer.mu.Unlock()
+	if refreshes != 0 {
+		t.Errorf("refreshes = %d, want 0", refreshes)
+	}
+
+	// refresh token 被吊销或缺失时刷新失败，会话不延长但仍然有效
+	issuer.revokeRefreshTokens()
+	if _, err := m.RefreshSession(context.Background(), session.ID); !errors.Is(err, ErrRefreshFailed) {
+		t.Errorf("RefreshSession() with revoked refresh token error = %v, want ErrRefreshFailed", err)
+	}
+	m.mu.Lock()
+	m.sessions[session.ID].OIDC.token.RefreshToken = ""
+	m.mu.Unlock()
+	if _, err := m.RefreshSession(context.Background(), session.ID); !errors.Is(err, ErrRefreshFailed) {
+		t.Errorf("RefreshSession() without refresh token error = %v, want ErrRefreshFailed", err)
+	}
+	if p, err := authenticate(m, session.ID); err != nil || p.Role != RoleOperator {
+		t.Errorf("Authenticate() after failed refresh = %+v, %v, want operator", p, err)
+	}
+	if s, ok := m.session(session.ID); !ok || !s.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("session after failed refresh extended or dropped: ok=%v", ok)
+	}
+}
+
+func TestOIDCConcurrentRefresh(t *testing.T) {
+	issuer := newMockIssuer(t)
+	issuer.setGroups("alice", "ml-users")
+	issuer.setGroups("bob", "ml-users")
+	m := newOIDCManager(t, issuer, "")
+
+	alice, err := login(t, m, issuer, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := login(t, m, issuer, "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 同一会话的并发刷新串行进行，refresh token 轮换时不能使用旧令牌；不同会话互不影响
+	var wg sync.WaitGroup
+	errs := make(chan error, 40)
+	for i := 0; i < 20; i++ {
+		for _, id := range []string{alice.ID, bob.ID} {
+			wg.Add(1)
+			go func(id string) {
+				defer wg.Done()
+				if _, err := m.RefreshSession(context.Background(), id); err != nil {
+					errs <- err
+				}
+			}(id)
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent refresh error = %v", err)
+	}
+	issuer.mu.Lock()
+	refreshes := issuer.refreshes
+	issuer.mu.Unlock()
+	if refreshes != 40 {
+		t.Errorf("refreshes = %d, want 40", refreshes)
+	}
+}
+
+func TestOIDCLogout(t *testing.T) {
+	issuer := newMockIssuer(t)
+	issuer.setGroups("alice", "ml-users")
+	m := newOIDCManager(t, issuer, "")
+
+	session, err := login(t, m, issuer, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	idToken := m.oidcSession(session).idToken
+
+	logoutURL := m.LogoutURL(session.ID)
+	u, err := url.Parse(logoutURL)
+	if err != nil || !strings.HasPrefix(logoutURL, issuer.srv.URL+"/logout?") {
+		t.Fatalf("LogoutURL() = %q, want provider end_session_endpoint", logoutURL)
+	}
+	q := u.Query()
+	if q.Get("id_token_hint") != idToken {
+		t.Errorf("id_token_hint = %q, want the session's ID token", q.Get("id_token_hint"))
+	}
+	if q.Get("post_logout_redirect_uri") != "https://dash.example.com/" || q.Get("client_id") != testClientID {
+		t.Errorf("logout query = %v", q)
+	}
+
+	if _, err := authenticate(m, session.ID); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Authenticate() after logout error = %v, want ErrUnauthenticated", err)
+	}
+	if again := m.LogoutURL(session.ID); again != "" {
+		t.Errorf("second LogoutURL() = %q, want empty", again)
+	}
+}
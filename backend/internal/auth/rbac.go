@@ -0,0 +1,222 @@
+package auth
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/config"
+)
+
+// Role 用户角色，权限逐级包含
+type Role int
+
+const (
+	RoleViewer   Role = iota // 只能查看监控数据
+	RoleOperator             // 可启动/停止/重启容器、查看容器日志
+	RoleAdmin                // 可删除容器、结束进程、修改配置
+)
+
+// 操作名称
+const (
+	ActionStart       = "start"
+	ActionStop        = "stop"
+	ActionRestart     = "restart"
+	ActionRemove      = "remove"
+	ActionLogs        = "logs"
+	ActionProcessKill = "process.kill"
+	ActionConfigEdit  = "config.update"
+)
+
+// actionRoles 各操作需要的最低角色
+var actionRoles = map[string]Role{
+	ActionStart:       RoleOperator,
+	ActionStop:        RoleOperator,
+	ActionRestart:     RoleOperator,
+	ActionRemove:      RoleAdmin,
+	ActionLogs:        RoleOperator,
+	ActionProcessKill: RoleAdmin,
+	ActionConfigEdit:  RoleAdmin,
+}
+
+// anonymousDenied 未启用认证时也拒绝匿名调用的破坏性操作
+// 修改配置可以开启认证并指定 OIDC provider，匿名调用者可借此取得 admin
+var anonymousDenied = map[string]bool{
+	ActionRemove:      true,
+	ActionProcessKill: true,
+	ActionConfigEdit:  true,
+}
+
+// String 返回角色名
+func (r Role) String() string {
+	switch r {
+	case RoleAdmin:
+		return "admin"
+	case RoleOperator:
+		return "operator"
+	default:
+		return "viewer"
+	}
+}
+
+// MarshalText 序列化为角色名
+func (r Role) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// ParseRole 解析角色名，未知角色视为 viewer
+func ParseRole(name string) (Role, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "admin":
+		return RoleAdmin, true
+	case "operator":
+		return RoleOperator, true
+	case "viewer", "":
+		return RoleViewer, true
+	default:
+		return RoleViewer, false
+	}
+}
+
+// AccessDeniedError 权限不足，Reason 可直接返回给调用方
+type AccessDeniedError struct {
+	Reason string
+}
+
+func (e *AccessDeniedError) Error() string {
+	return e.Reason
+}
+
+// ContainerTarget 容器操作的目标
+type ContainerTarget struct {
+	ID     string
+	Name   string
+	Labels map[string]string
+}
+
+// HasRole 检查调用方是否具有指定角色
+// 未启用认证时 principal 为 nil，保持原有的无限制行为；破坏性操作另见 AuthorizeAction
+func HasRole(p *Principal, role Role) bool {
+	return p == nil || p.Role >= role
+}
+
+// RequireRole 检查角色，不满足时返回 AccessDeniedError
+func RequireRole(p *Principal, role Role) error {
+	if HasRole(p, role) {
+		return nil
+	}
+	return &AccessDeniedError{
+		Reason: fmt.Sprintf("role %s required, user %s has role %s", role, p.Username, p.Role),
+	}
+}
+
+// AuthorizeAction 检查调用方能否执行某个操作（不针对具体容器）
+// 删除容器、结束进程和修改配置需要已认证的 admin，未启用认证时一律拒绝
+func AuthorizeAction(p *Principal, action string) error {
+	required, ok := actionRoles[action]
+	if !ok {
+		required = RoleAdmin
+	}
+	if p == nil && anonymousDenied[action] {
+		return &AccessDeniedError{
+			Reason: fmt.Sprintf("action %q requires an authenticated %s, enable auth to use it", action, required),
+		}
+	}
+	if HasRole(p, required) {
+		return nil
+	}
+	return &AccessDeniedError{
+		Reason: fmt.Sprintf("action %q requires role %s, user %s has role %s", action, required, p.Username, p.Role),
+	}
+}
+
+// AuthorizeContainer 检查调用方能否对指定容器执行操作
+// 先检查角色，再检查适用于该用户的策略；admin 不受策略限制，
+// 没有任何策略适用于该用户时只按角色判断
+func (m *Manager) AuthorizeContainer(p *Principal, action string, target ContainerTarget) error {
+	if err := AuthorizeAction(p, action); err != nil {
+		return err
+	}
+	if p == nil || p.Role >= RoleAdmin {
+		return nil
+	}
+
+	m.mu.RLock()
+	policies := m.cfg.Policies
+	m.mu.RUnlock()
+
+	applicable := 0
+	for _, policy := range policies {
+		if !policyApplies(policy, p) {
+			continue
+		}
+		applicable++
+		if policyAllows(policy, p, action, target) {
+			return nil
+		}
+	}
+	if applicable == 0 {
+		return nil
+	}
+
+	return &AccessDeniedError{
+		Reason: fmt.Sprintf("no policy allows user %s to %s container %s", p.Username, action, target.Name),
+	}
+}
+
+// policyApplies 策略是否适用于该用户
+func policyApplies(policy config.PolicyConfig, p *Principal) bool {
+	if policy.User != "" {
+		return policy.User == p.Username
+	}
+	if policy.Role != "" {
+		role, _ := ParseRole(policy.Role)
+		return role == p.Role
+	}
+	return true
+}
+
+// policyAllows 策略是否允许对目标容器执行该操作
+// 名称模式与标签条件需要同时满足；值中的 {user} 替换为当前用户名
+func policyAllows(policy config.PolicyConfig, p *Principal, action string, target ContainerTarget) bool {
+	if len(policy.Actions) > 0 && !containsAction(policy.Actions, action) {
+		return false
+	}
+
+	if len(policy.ContainerNames) > 0 {
+		matched := false
+		for _, pattern := range policy.ContainerNames {
+			if ok, _ := path.Match(expandUser(pattern, p), target.Name); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	for key, value := range policy.ContainerLabels {
+		actual, ok := target.Labels[key]
+		if !ok || actual != expandUser(value, p) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// containsAction 判断操作列表是否包含指定操作，"*" 表示全部
+func containsAction(actions []string, action string) bool {
+	for _, a := range actions {
+		if a == "*" || a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// expandUser 替换 {user} 占位符
+func expandUser(s string, p *Principal) string {
+	return strings.ReplaceAll(s, "{user}", p.Username)
+}
@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAuthorizeActionAnonymous(t *testing.T) {
+	// 未启用认证时普通容器操作不受限制，破坏性操作必须拒绝
+	for _, action := range []string{ActionStart, ActionStop, ActionRestart, ActionLogs} {
+		if err := AuthorizeAction(nil, action); err != nil {
+			t.Errorf("AuthorizeAction(nil, %q) = %v, want nil", action, err)
+		}
+	}
+	for _, action := range []string{ActionRemove, ActionProcessKill, ActionConfigEdit} {
+		err := AuthorizeAction(nil, action)
+		var denied *AccessDeniedError
+		if !errors.As(err, &denied) {
+			t.Errorf("AuthorizeAction(nil, %q) = %v, want AccessDeniedError", action, err)
+		}
+	}
+}
+
+func TestAuthorizeActionRoles(t *testing.T) {
+	tests := []struct {
+		role   Role
+		action string
+		allow  bool
+	}{
+		{RoleViewer, ActionRestart, false},
+		{RoleOperator, ActionRestart, true},
+		{RoleViewer, ActionLogs, false},
+		{RoleOperator, ActionLogs, true},
+		{RoleOperator, ActionRemove, false},
+		{RoleOperator, ActionProcessKill, false},
+		{RoleAdmin, ActionRemove, true},
+		{RoleAdmin, ActionProcessKill, true},
+	}
+	for _, tt := range tests {
+		p := &Principal{Username: "u", Role: tt.role}
+		err := AuthorizeAction(p, tt.action)
+		if (err == nil) != tt.allow {
+			t.Errorf("AuthorizeAction(%s, %q) = %v, want allow=%v", tt.role, tt.action, err, tt.allow)
+		}
+	}
+}
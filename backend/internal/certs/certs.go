@@ -0,0 +1,216 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/config"
+)
+
+// reloadInterval 检查证书文件变更的间隔
+const reloadInterval = 10 * time.Second
+
+// fileStamp 文件的修改时间和大小，用于判断是否变更
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// Manager 持有当前证书和客户端 CA，文件变更后自动重新加载
+// 重新加载失败时保留旧证书继续服务
+type Manager struct {
+	mu       sync.RWMutex
+	cfg      config.TLSConfig
+	cert     *tls.Certificate
+	clientCA *x509.CertPool
+	stamps   map[string]fileStamp
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewManager 加载证书；启用 selfSigned 且证书文件不存在时先生成自签名证书
+func NewManager(cfg config.TLSConfig) (*Manager, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, fmt.Errorf("server.tls.certFile and server.tls.keyFile are required")
+	}
+	if _, err := parseClientAuth(cfg.ClientAuth); err != nil {
+		return nil, err
+	}
+	if cfg.ClientAuth != "" && cfg.ClientAuth != "none" && cfg.ClientCAFile == "" {
+		return nil, fmt.Errorf("server.tls.clientCaFile is required when clientAuth is %q", cfg.ClientAuth)
+	}
+
+	if cfg.SelfSigned && !fileExists(cfg.CertFile) && !fileExists(cfg.KeyFile) {
+		if err := GenerateSelfSigned(cfg.CertFile, cfg.KeyFile, cfg.SelfSignedHosts); err != nil {
+			return nil, fmt.Errorf("failed to generate self-signed certificate: %w", err)
+		}
+		log.Printf("TLS: generated self-signed certificate %s", cfg.CertFile)
+	}
+
+	m := &Manager{cfg: cfg, stamps: make(map[string]fileStamp)}
+	if err := m.reload(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TLSConfig 返回用于 http.Server 的 TLS 配置，每次握手读取最新的证书和客户端 CA
+func (m *Manager) TLSConfig() *tls.Config {
+	clientAuth, _ := parseClientAuth(m.cfg.ClientAuth)
+	base := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: []string{"h2", "http/1.1"},
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			m.mu.RLock()
+			defer m.mu.RUnlock()
+			return m.cert, nil
+		},
+	}
+	if clientAuth == tls.NoClientCert {
+		return base
+	}
+
+	// 客户端 CA 也可能被替换，因此按连接生成配置
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		cfg := base.Clone()
+		cfg.GetConfigForClient = nil
+		cfg.ClientAuth = clientAuth
+		m.mu.RLock()
+		cfg.ClientCAs = m.clientCA
+		m.mu.RUnlock()
+		return cfg, nil
+	}
+	return base
+}
+
+// Start 启动后台协程，定期检查证书文件是否变更
+func (m *Manager) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.done = make(chan struct{})
+
+	go func() {
+		defer close(m.done)
+		ticker := time.NewTicker(reloadInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if !m.changed() {
+					continue
+				}
+				if err := m.reload(); err != nil {
+					log.Printf("TLS: failed to reload certificate, keeping the previous one: %v", err)
+					continue
+				}
+				log.Printf("TLS: certificate reloaded from %s", m.cfg.CertFile)
+			}
+		}
+	}()
+}
+
+// Stop 停止后台检查
+func (m *Manager) Stop() {
+	if m.cancel == nil {
+		return
+	}
+	m.cancel()
+	<-m.done
+}
+
+// watchedFiles 需要监视的文件
+func (m *Manager) watchedFiles() []string {
+	files := []string{m.cfg.CertFile, m.cfg.KeyFile}
+	if m.cfg.ClientCAFile != "" {
+		files = append(files, m.cfg.ClientCAFile)
+	}
+	return files
+}
+
+// changed 是否有文件的修改时间或大小发生变化
+func (m *Manager) changed() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, path := range m.watchedFiles() {
+		info, err := os.Stat(path)
+		if err != nil {
+			// 证书轮换工具可能先删除再写入，等文件出现后再加载
+			continue
+		}
+		if stamp := m.stamps[path]; !stamp.modTime.Equal(info.ModTime()) || stamp.size != info.Size() {
+			return true
+		}
+	}
+	return false
+}
+
+// reload 重新读取证书、私钥和客户端 CA
+func (m *Manager) reload() error {
+	stamps := make(map[string]fileStamp)
+	for _, path := range m.watchedFiles() {
+		if info, err := os.Stat(path); err == nil {
+			stamps[path] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+
+	cert, err := tls.LoadX509KeyPair(m.cfg.CertFile, m.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load certificate: %w", err)
+	}
+	if leaf, err := x509.ParseCertificate(cert.Certificate[0]); err == nil {
+		cert.Leaf = leaf
+		if time.Now().After(leaf.NotAfter) {
+			log.Printf("TLS: warning: certificate %s expired at %s", m.cfg.CertFile, leaf.NotAfter.Format(time.RFC3339))
+		}
+	}
+
+	var pool *x509.CertPool
+	if m.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(m.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client CA: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in %s", m.cfg.ClientCAFile)
+		}
+	}
+
+	m.mu.Lock()
+	m.cert = &cert
+	m.clientCA = pool
+	m.stamps = stamps
+	m.mu.Unlock()
+	return nil
+}
+
+// parseClientAuth 解析客户端证书校验模式
+func parseClientAuth(mode string) (tls.ClientAuthType, error) {
+	switch strings.ToLower(mode) {
+	case "", "none":
+		return tls.NoClientCert, nil
+	case "optional":
+		return tls.VerifyClientCertIfGiven, nil
+	case "require":
+		return tls.RequireAndVerifyClientCert, nil
+	default:
+		return tls.NoClientCert, fmt.Errorf("unknown server.tls.clientAuth %q, expected none, optional or require", mode)
+	}
+}
+
+// fileExists 文件是否存在
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,146 @@
+package cluster
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/config"
+)
+
+const (
+	// minBackoff / maxBackoff agent 重连的等待时间范围，每次失败翻倍
+	minBackoff = time.Second
+	maxBackoff = 30 * time.Second
+	// dialTimeout 连接 hub 的超时
+	dialTimeout = 10 * time.Second
+)
+
+// RunAgent 连接 hub 并按 reportInterval 上报本机采样，断开后自动重连，直到 ctx 取消
+func RunAgent(ctx context.Context, cfg config.ClusterConfig) error {
+	url := agentURL(cfg.HubURL)
+	dialer, err := newDialer(cfg)
+	if err != nil {
+		return err
+	}
+	id := NodeID(cfg)
+	localID = id
+	ApplyConfig(cfg)
+	log.Printf("Agent: reporting as node %s to %s every %dms", id, url, cfg.ReportInterval)
+
+	backoff := minBackoff
+	for {
+		connected, err := runSession(ctx, dialer, url, id, cfg)
+		if ctx.Err() != nil {
+			return nil
+		}
+		// hub 拒绝连接（如同一节点 ID 已在线）时不重置等待时间
+		if connected && !websocket.IsCloseError(err, websocket.ClosePolicyViolation) {
+			backoff = minBackoff
+		}
+		log.Printf("Agent: connection to hub lost: %v (retrying in %v)", err, backoff)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// agentURL 将 hub 地址转换为 agent 的 WebSocket 地址
+func agentURL(hubURL string) string {
+	url := strings.TrimRight(hubURL, "/")
+	if rest, ok := strings.CutPrefix(url, "https://"); ok {
+		url = "wss://" + rest
+	} else if rest, ok := strings.CutPrefix(url, "http://"); ok {
+		url = "ws://" + rest
+	}
+	return url + "/api/agent/ws"
+}
+
+// newDialer 创建连接 hub 的 dialer，配置了 caFile 时用其校验 hub 的证书
+func newDialer(cfg config.ClusterConfig) (*websocket.Dialer, error) {
+	dialer := &websocket.Dialer{
+		Proxy:             http.ProxyFromEnvironment,
+		HandshakeTimeout:  dialTimeout,
+		EnableCompression: true,
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cluster.caFile: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.CAFile)
+		}
+		dialer.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+	return dialer, nil
+}
+
+// runSession 建立一次连接并持续上报，返回是否成功连接过以及断开的原因
+func runSession(ctx context.Context, dialer *websocket.Dialer, url, id string, cfg config.ClusterConfig) (bool, error) {
+	header := http.Header{"Authorization": {"Bearer " + cfg.Token}}
+	conn, resp, err := dialer.DialContext(ctx, url, header)
+	if err != nil {
+		if resp != nil {
+			return false, fmt.Errorf("%w (HTTP %d)", err, resp.StatusCode)
+		}
+		return false, err
+	}
+	defer conn.Close()
+
+	if err := conn.WriteJSON(agentMessage{Type: messageHello, NodeID: id}); err != nil {
+		return false, err
+	}
+	log.Printf("Agent: connected to %s", url)
+
+	// hub 不发送数据消息，读循环只用于处理 ping 和关闭帧
+	readErr := make(chan error, 1)
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				readErr <- err
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(time.Duration(cfg.ReportInterval) * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		sample := Collect()
+		conn.SetWriteDeadline(time.Now().Add(agentWriteWait))
+		if err := conn.WriteJSON(agentMessage{Type: messageSample, Sample: &sample}); err != nil {
+			// hub 关闭连接时读循环先收到关闭帧，其中的原因更有用
+			select {
+			case readErr := <-readErr:
+				return true, readErr
+			default:
+				return true, err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, "agent shutting down"), time.Now().Add(agentWriteWait))
+			return true, ctx.Err()
+		case err := <-readErr:
+			return true, err
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,406 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"log"
+	"maps"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/config"
+	"github.com/dat-G/MLServer_Dash/backend/internal/docker"
+	"github.com/dat-G/MLServer_Dash/backend/internal/models"
+	"github.com/dat-G/MLServer_Dash/backend/internal/monitor"
+)
+
+// 节点状态
+const (
+	StatusOnline = "online"
+	StatusStale  = "stale" // 已断开或超过 staleAfter 未收到上报
+)
+
+// ErrNodeNotFound 节点不存在
+var ErrNodeNotFound = errors.New("node not found")
+
+// ErrLocalNode 本机节点不能删除
+var ErrLocalNode = errors.New("the local node cannot be removed")
+
+// node 一个节点的状态和历史采样
+type node struct {
+	id          string
+	address     string
+	local       bool
+	conn        *agentConn // 当前的 agent 连接，本机节点或已断开时为 nil
+	connectedAt time.Time
+	lastSeen    time.Time
+	latest      *models.NodeSample
+	history     []models.NodeHistoryPoint // 环形缓冲
+	next        int
+	idleSince   map[string]time.Time // gpuKey -> 开始空闲的时间
+}
+
+var (
+	mu    sync.RWMutex
+	nodes = make(map[string]*node)
+
+	// settings Start 时的集群配置，集群配置修改后需要重启
+	settings config.ClusterConfig
+	localID  string
+
+	// localMetadata 本机的标签和备注，overrides hub 为各节点设置的标签和备注，均可热加载
+	localMetadata config.NodeMetadata
+	overrides     map[string]config.NodeMetadata
+)
+
+// Start 初始化节点注册表，includeLocal 的 hub 模式下定期采集本机，ctx 取消后停止
+// standalone 模式不单独采集，本机节点沿用 system 主题和 /api/system 的采集结果，见 RecordLocal
+func Start(ctx context.Context, cfg config.ClusterConfig) {
+	settings = cfg
+	localID = NodeID(cfg)
+	ApplyConfig(cfg)
+	if cfg.Mode != config.ModeHub {
+		mu.Lock()
+		getOrCreateLocked(localID).local = true
+		mu.Unlock()
+		return
+	}
+
+	log.Printf("Cluster: hub mode, accepting agents at /api/agent/ws (stale after %ds)", cfg.StaleAfter)
+	if !cfg.IncludeLocal {
+		return
+	}
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		sampleLocal(ctx, time.Duration(cfg.ReportInterval)*time.Millisecond)
+	}()
+}
+
+// workers 本机采样 goroutine
+var workers sync.WaitGroup
+
+// Wait 等待本机采样退出，需在 ctx 取消后调用
+func Wait() {
+	workers.Wait()
+}
+
+// NodeID 返回本机的节点 ID，未配置时使用主机名
+func NodeID(cfg config.ClusterConfig) string {
+	if cfg.NodeID != "" {
+		return cfg.NodeID
+	}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return hostname
+	}
+	return "local"
+}
+
+// ApplyConfig 应用新的节点标签和备注，其余集群配置需重启生效
+func ApplyConfig(cfg config.ClusterConfig) {
+	mu.Lock()
+	defer mu.Unlock()
+	localMetadata = config.NodeMetadata{Labels: cfg.Labels, Notes: cfg.Notes}
+	overrides = cfg.Nodes
+}
+
+// sampleLocal 按间隔采集本机并记录，直到 ctx 取消
+func sampleLocal(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		recordLocal(Collect())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RecordLocal standalone 模式下将 system 主题或 /api/system 采集的系统信息记录为本机节点的采样
+// 与上一次采样的间隔不足 reportInterval 时忽略，容器列表随之刷新
+// 本机不再单独调用 GetSystemInfo，网络和磁盘速率等按两次调用之间计算的数据不会被打乱
+func RecordLocal(info models.SystemInfo) {
+	if settings.Mode != config.ModeStandalone || !localDue(time.Now()) {
+		return
+	}
+	sample := models.NodeSample{
+		Timestamp:  time.Now(),
+		System:     info,
+		Containers: docker.GetContainers(),
+	}
+	attachContainers(&sample)
+	recordLocal(sample)
+}
+
+// refreshLocal standalone 模式下本机没有足够新的采样时（没有客户端订阅 system 主题）按需采集一次
+// 在集群查询之前调用，此时不会与 system 主题的采集器同时运行
+func refreshLocal() {
+	if settings.Mode != config.ModeStandalone {
+		return
+	}
+	refreshMu.Lock()
+	defer refreshMu.Unlock()
+	// 按 2 倍间隔判断，system 主题按 reportInterval 左右的间隔推送时不会触发额外的采集
+	if localDue(time.Now().Add(-time.Duration(settings.ReportInterval) * time.Millisecond)) {
+		recordLocal(Collect())
+	}
+}
+
+// refreshMu 串行化按需采集，并发的查询只采集一次
+var refreshMu sync.Mutex
+
+// localDue 本机最近一次采样是否早于 now 之前的 reportInterval
+func localDue(now time.Time) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	n, ok := nodes[localID]
+	if !ok || n.latest == nil {
+		return true
+	}
+	return now.Sub(n.latest.Timestamp) >= time.Duration(settings.ReportInterval)*time.Millisecond
+}
+
+// recordLocal 记录本机采样，标签和备注使用本机配置（hub 设置的在查询时合并）
+func recordLocal(sample models.NodeSample) {
+	mu.Lock()
+	defer mu.Unlock()
+	sample.System.NodeID = localID
+	sample.System.Labels = maps.Clone(localMetadata.Labels)
+	sample.System.Notes = localMetadata.Notes
+	n := getOrCreateLocked(localID)
+	n.local = true
+	n.record(sample, sample.Timestamp)
+}
+
+// Collect 采集本机的系统信息和容器列表，GPU 进程补充所在容器的名称，附带本机配置的标签和备注
+func Collect() models.NodeSample {
+	sample := models.NodeSample{
+		Timestamp:  time.Now(),
+		System:     monitor.GetSystemInfo(),
+		Containers: docker.GetContainers(),
+	}
+	attachContainers(&sample)
+
+	mu.RLock()
+	sample.System.NodeID = localID
+	sample.System.Labels = maps.Clone(localMetadata.Labels)
+	sample.System.Notes = localMetadata.Notes
+	mu.RUnlock()
+	return sample
+}
+
+// Annotate 为本机的系统信息补充节点 ID、标签和备注（含 hub 为本机设置的），用于 /api/system 和 system 主题
+func Annotate(info *models.SystemInfo) {
+	mu.RLock()
+	defer mu.RUnlock()
+	info.NodeID = localID
+	info.Labels, info.Notes = merge(localID, localMetadata.Labels, localMetadata.Notes)
+}
+
+// merge 将 hub 为节点设置的标签和备注合并到节点上报的之上，需持有 mu
+func merge(id string, labels map[string]string, notes string) (map[string]string, string) {
+	merged := maps.Clone(labels)
+	if merged == nil {
+		merged = map[string]string{}
+	}
+	if o, ok := overrides[id]; ok {
+		maps.Copy(merged, o.Labels)
+		if o.Notes != "" {
+			notes = o.Notes
+		}
+	}
+	return merged, notes
+}
+
+// metadata 节点当前的标签和备注，需持有 mu
+func (n *node) metadata() (map[string]string, string) {
+	if n.latest == nil {
+		return merge(n.id, nil, "")
+	}
+	return merge(n.id, n.latest.System.Labels, n.latest.System.Notes)
+}
+
+// getOrCreateLocked 返回节点，不存在时创建，需持有 mu
+func getOrCreateLocked(id string) *node {
+	n, ok := nodes[id]
+	if !ok {
+		n = &node{id: id}
+		nodes[id] = n
+	}
+	return n
+}
+
+// record 保存最新采样并追加历史，需持有 mu
+func (n *node) record(sample models.NodeSample, received time.Time) {
+	n.latest = &sample
+	n.lastSeen = received
+	n.trackIdle(sample)
+
+	point := models.NodeHistoryPoint{
+		Timestamp:     sample.Timestamp,
+		CPUPercent:    sample.System.CPU.Percent,
+		MemoryPercent: sample.System.Memory.Percent,
+	}
+	for _, gpu := range sample.System.GPU {
+		point.GPUUtilization = append(point.GPUUtilization, gpu.Utilization)
+		point.GPUMemoryPercent = append(point.GPUMemoryPercent, gpu.Memory.Percent)
+	}
+
+	size := settings.HistorySize
+	if size <= 0 {
+		size = 1
+	}
+	if len(n.history) < size {
+		n.history = append(n.history, point)
+		return
+	}
+	n.history[n.next] = point
+	n.next = (n.next + 1) % size
+}
+
+// status 节点状态：本机始终在线，agent 断开或超过 staleAfter 未上报时为 stale
+func (n *node) status(now time.Time) string {
+	if n.local {
+		return StatusOnline
+	}
+	staleAfter := time.Duration(settings.StaleAfter) * time.Second
+	if n.conn == nil || now.Sub(n.lastSeen) > staleAfter {
+		return StatusStale
+	}
+	return StatusOnline
+}
+
+// summary 生成节点概览，需持有 mu
+func (n *node) summary(now time.Time) models.NodeSummary {
+	s := models.NodeSummary{
+		ID:       n.id,
+		Status:   n.status(now),
+		Local:    n.local,
+		Address:  n.address,
+		LastSeen: n.lastSeen,
+	}
+	s.Labels, s.Notes = n.metadata()
+	if n.conn != nil {
+		connectedAt := n.connectedAt
+		s.ConnectedAt = &connectedAt
+	}
+	if n.latest == nil {
+		return s
+	}
+
+	sys := n.latest.System
+	s.Hostname = sys.Hostname
+	s.Uptime = sys.Uptime
+	s.CPUPercent = sys.CPU.Percent
+	s.MemoryPercent = sys.Memory.Percent
+	s.GPUCount = len(sys.GPU)
+	if len(sys.GPU) > 0 {
+		var total float64
+		for _, gpu := range sys.GPU {
+			total += gpu.Utilization
+		}
+		s.GPUUtilization = total / float64(len(sys.GPU))
+	}
+	for _, c := range n.latest.Containers {
+		if c.State == "running" {
+			s.Containers++
+		}
+	}
+	return s
+}
+
+// Nodes 返回标签满足 selector 的节点概览，按 ID 排序
+func Nodes(selector Selector) []models.NodeSummary {
+	refreshLocal()
+	mu.RLock()
+	defer mu.RUnlock()
+
+	now := time.Now()
+	result := make([]models.NodeSummary, 0, len(nodes))
+	for _, n := range nodes {
+		s := n.summary(now)
+		if selector.Matches(s.Labels) {
+			result = append(result, s)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// Node 返回单个节点的概览
+func Node(id string) (models.NodeSummary, error) {
+	refreshLocal()
+	mu.RLock()
+	defer mu.RUnlock()
+
+	n, ok := nodes[id]
+	if !ok {
+		return models.NodeSummary{}, ErrNodeNotFound
+	}
+	return n.summary(time.Now()), nil
+}
+
+// Latest 返回节点最近一次采样，标签和备注已合并 hub 的设置，节点尚未上报时返回 ErrNodeNotFound
+func Latest(id string) (*models.NodeSample, error) {
+	refreshLocal()
+	mu.RLock()
+	defer mu.RUnlock()
+
+	n, ok := nodes[id]
+	if !ok || n.latest == nil {
+		return nil, ErrNodeNotFound
+	}
+	sample := *n.latest
+	sample.System.NodeID = n.id
+	sample.System.Labels, sample.System.Notes = n.metadata()
+	return &sample, nil
+}
+
+// History 按时间顺序返回节点的历史采样，limit 大于 0 时只返回最近的 limit 条
+func History(id string, limit int) ([]models.NodeHistoryPoint, error) {
+	refreshLocal()
+	mu.RLock()
+	defer mu.RUnlock()
+
+	n, ok := nodes[id]
+	if !ok {
+		return nil, ErrNodeNotFound
+	}
+	points := make([]models.NodeHistoryPoint, 0, len(n.history))
+	points = append(points, n.history[n.next:]...)
+	points = append(points, n.history[:n.next]...)
+	if limit > 0 && len(points) > limit {
+		points = points[len(points)-limit:]
+	}
+	return points, nil
+}
+
+// Remove 删除节点（如已下线的机器），仍在连接的 agent 会被断开
+func Remove(id string) error {
+	mu.Lock()
+	n, ok := nodes[id]
+	if !ok {
+		mu.Unlock()
+		return ErrNodeNotFound
+	}
+	if n.local {
+		mu.Unlock()
+		return ErrLocalNode
+	}
+	delete(nodes, id)
+	conn := n.conn
+	mu.Unlock()
+
+	if conn != nil {
+		conn.close(closeRemoved)
+	}
+	return nil
+}
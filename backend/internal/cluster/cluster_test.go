@@ -0,0 +1,103 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/config"
+	"github.com/dat-G/MLServer_Dash/backend/internal/models"
+)
+
+// useSettings 替换集群配置并清空节点注册表，测试结束后恢复
+func useSettings(t *testing.T, cfg config.ClusterConfig) {
+	t.Helper()
+	mu.Lock()
+	oldSettings, oldID, oldNodes := settings, localID, nodes
+	settings, localID, nodes = cfg, "test-node", make(map[string]*node)
+	mu.Unlock()
+	t.Cleanup(func() {
+		mu.Lock()
+		settings, localID, nodes = oldSettings, oldID, oldNodes
+		mu.Unlock()
+	})
+}
+
+// rewindLocal 将本机最近一次采样的时间往前调 d
+func rewindLocal(d time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+	nodes[localID].latest.Timestamp = nodes[localID].latest.Timestamp.Add(-d)
+}
+
+func TestRecordLocalThrottled(t *testing.T) {
+	useSettings(t, config.ClusterConfig{Mode: config.ModeStandalone, ReportInterval: 5000, HistorySize: 10})
+
+	info := models.SystemInfo{Hostname: "gpu-01", CPU: models.CPUInfo{Percent: 10}}
+	RecordLocal(info)
+	// 间隔不足 reportInterval 的采集结果不记入历史
+	info.CPU.Percent = 20
+	RecordLocal(info)
+
+	points, err := History("test-node", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 1 || points[0].CPUPercent != 10 {
+		t.Fatalf("History() = %+v, want one point with cpu 10", points)
+	}
+
+	rewindLocal(5 * time.Second)
+	RecordLocal(info)
+	points, _ = History("test-node", 0)
+	if len(points) != 2 || points[1].CPUPercent != 20 {
+		t.Errorf("History() after interval = %+v, want a second point with cpu 20", points)
+	}
+
+	node, err := Node("test-node")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !node.Local || node.Hostname != "gpu-01" || node.Status != StatusOnline {
+		t.Errorf("Node() = %+v", node)
+	}
+}
+
+func TestRecordLocalIgnoredInHubMode(t *testing.T) {
+	// hub 模式下本机由 sampleLocal 按间隔采集，system 主题的结果不重复记录
+	useSettings(t, config.ClusterConfig{Mode: config.ModeHub, ReportInterval: 5000, HistorySize: 10})
+
+	RecordLocal(models.SystemInfo{Hostname: "hub"})
+	if _, err := Node("test-node"); err != ErrNodeNotFound {
+		t.Errorf("Node() error = %v, want ErrNodeNotFound", err)
+	}
+}
+
+func TestRegisterRejectsOnlineNodeID(t *testing.T) {
+	useSettings(t, config.ClusterConfig{Mode: config.ModeHub, ReportInterval: 5000, HistorySize: 10})
+
+	first, second := &agentConn{}, &agentConn{}
+	if _, ok := register("gpu-02", "10.0.0.2", first); !ok {
+		t.Fatal("register() of a new node = false")
+	}
+
+	// 节点在线时同一 ID 的其他连接被拒绝，原连接不受影响
+	reason, ok := register("gpu-02", "10.0.0.66", second)
+	if ok || reason != closeDuplicate {
+		t.Fatalf("register() of an online node = %v, %v, want closeDuplicate", reason, ok)
+	}
+	mu.Lock()
+	n := nodes["gpu-02"]
+	conn, address := n.conn, n.address
+	mu.Unlock()
+	if conn != first || address != "10.0.0.2" {
+		t.Errorf("node connection replaced: address=%s", address)
+	}
+
+	// 原连接断开后可以重新连接
+	if !unregister("gpu-02", first) {
+		t.Fatal("unregister() = false")
+	}
+	if _, ok := register("gpu-02", "10.0.0.2", second); !ok {
+		t.Error("register() after the previous connection closed = false")
+	}
+}
@@ -0,0 +1,167 @@
+package cluster
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/models"
+	"github.com/dat-G/MLServer_Dash/backend/internal/monitor"
+)
+
+// idleUtilization 利用率低于该值（%）且没有计算进程的 GPU 视为空闲
+const idleUtilization = 5
+
+// GPUFilter /api/cluster/gpus 的筛选条件，零值表示不限制
+type GPUFilter struct {
+	MinFreeMemory uint64        // 最少空闲显存（字节）
+	Model         string        // 型号包含的字符串，不区分大小写
+	Nodes         []string      // 节点 ID
+	Idle          *bool         // 是否空闲
+	IdleFor       time.Duration // 至少已空闲的时长
+	IncludeStale  bool          // 是否包含 stale 节点上的 GPU
+	Selector      Selector      // 节点标签选择器
+}
+
+// match 判断 GPU 是否满足筛选条件
+func (f GPUFilter) match(gpu models.ClusterGPU, now time.Time) bool {
+	if gpu.NodeStatus == StatusStale && !f.IncludeStale {
+		return false
+	}
+	if gpu.MemoryFree < f.MinFreeMemory {
+		return false
+	}
+	if f.Model != "" && !strings.Contains(strings.ToLower(gpu.Model), strings.ToLower(f.Model)) {
+		return false
+	}
+	if len(f.Nodes) > 0 && !contains(f.Nodes, gpu.Node) {
+		return false
+	}
+	if !f.Selector.Matches(gpu.NodeLabels) {
+		return false
+	}
+	if f.Idle != nil && gpu.Idle != *f.Idle {
+		return false
+	}
+	if f.IdleFor > 0 && (gpu.IdleSince == nil || now.Sub(*gpu.IdleSince) < f.IdleFor) {
+		return false
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// gpuIdle 没有计算进程且利用率低于 idleUtilization
+func gpuIdle(gpu models.GPUInfo) bool {
+	return len(gpu.Processes) == 0 && gpu.Utilization < idleUtilization
+}
+
+// gpuKey 在节点内标识一块 GPU，优先使用 UUID（序号在重启后可能变化）
+func gpuKey(gpu models.GPUInfo) string {
+	if gpu.UUID != "" {
+		return gpu.UUID
+	}
+	return strconv.Itoa(gpu.Index)
+}
+
+// trackIdle 根据新的采样更新每块 GPU 开始空闲的时间，需持有 mu
+func (n *node) trackIdle(sample models.NodeSample) {
+	seen := make(map[string]time.Time, len(sample.System.GPU))
+	for _, gpu := range sample.System.GPU {
+		if !gpuIdle(gpu) {
+			continue
+		}
+		key := gpuKey(gpu)
+		since, ok := n.idleSince[key]
+		if !ok {
+			since = sample.Timestamp
+		}
+		seen[key] = since
+	}
+	n.idleSince = seen
+}
+
+// attachContainers 根据容器列表补充 GPU 进程所在容器的名称
+func attachContainers(sample *models.NodeSample) {
+	for i := range sample.System.GPU {
+		procs := sample.System.GPU[i].Processes
+		for j := range procs {
+			if procs[j].ContainerID == "" {
+				continue
+			}
+			for _, c := range sample.Containers {
+				if strings.HasPrefix(procs[j].ContainerID, c.ID) || strings.HasPrefix(c.ID, procs[j].ContainerID) {
+					procs[j].Container = c.Name
+					break
+				}
+			}
+		}
+	}
+}
+
+// GPUs 列出所有节点上满足条件的 GPU，按节点 ID 和序号排序
+func GPUs(filter GPUFilter) []models.ClusterGPU {
+	refreshLocal()
+	mu.RLock()
+	defer mu.RUnlock()
+
+	now := time.Now()
+	result := []models.ClusterGPU{}
+	for _, n := range nodes {
+		if n.latest == nil {
+			continue
+		}
+		status := n.status(now)
+		labels, _ := n.metadata()
+		for _, gpu := range n.latest.System.GPU {
+			item := models.ClusterGPU{
+				Node:            n.id,
+				NodeStatus:      status,
+				NodeLabels:      labels,
+				Index:           gpu.Index,
+				UUID:            gpu.UUID,
+				Model:           gpu.Name,
+				MemoryTotal:     gpu.Memory.Total,
+				MemoryUsed:      gpu.Memory.Used,
+				MemoryFree:      gpu.Memory.Free,
+				MemoryFreeHuman: monitor.FormatBytes(gpu.Memory.Free),
+				Utilization:     gpu.Utilization,
+				Temperature:     gpu.Temperature,
+				Idle:            gpuIdle(gpu),
+				Processes:       gpu.Processes,
+				Containers:      []string{},
+				SampledAt:       n.latest.Timestamp,
+			}
+			if item.Processes == nil {
+				item.Processes = []models.GPUProcess{}
+			}
+			if since, ok := n.idleSince[gpuKey(gpu)]; ok {
+				item.IdleSince = &since
+			}
+			for _, p := range gpu.Processes {
+				if p.Container != "" && !contains(item.Containers, p.Container) {
+					item.Containers = append(item.Containers, p.Container)
+				}
+			}
+			if filter.match(item, now) {
+				result = append(result, item)
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Node != result[j].Node {
+			return result[i].Node < result[j].Node
+		}
+		return result[i].Index < result[j].Index
+	})
+	return result
+}
@@ -0,0 +1,217 @@
+package cluster
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/config"
+	"github.com/dat-G/MLServer_Dash/backend/internal/models"
+)
+
+// agent 协议消息类型
+const (
+	messageHello  = "hello"  // 连接后的第一条消息，声明节点 ID
+	messageSample = "sample" // 一次采样
+)
+
+// agentMessage agent 发送给 hub 的消息
+type agentMessage struct {
+	Type   string             `json:"type"`
+	NodeID string             `json:"node_id,omitempty"`
+	Sample *models.NodeSample `json:"sample,omitempty"`
+}
+
+const (
+	// helloTimeout 连接后等待 hello 消息的时间
+	helloTimeout = 10 * time.Second
+	// maxAgentMessage agent 单条消息的最大字节数
+	maxAgentMessage = 4 << 20
+	// agentWriteWait 发送关闭帧的超时
+	agentWriteWait = 5 * time.Second
+)
+
+// 关闭 agent 连接的原因
+var (
+	closeDuplicate = closeReason{websocket.ClosePolicyViolation, "node id is already connected"}
+	closeRemoved   = closeReason{websocket.ClosePolicyViolation, "node removed"}
+	closeShutdown  = closeReason{websocket.CloseGoingAway, "server shutting down"}
+)
+
+type closeReason struct {
+	code int
+	text string
+}
+
+// validNodeID 节点 ID 格式
+var validNodeID = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,62}$`)
+
+// agentUpgrader agent 不是浏览器，使用 token 认证，不检查 Origin
+var agentUpgrader = websocket.Upgrader{
+	ReadBufferSize:    4096,
+	WriteBufferSize:   1024,
+	EnableCompression: true,
+	CheckOrigin:       func(r *http.Request) bool { return true },
+}
+
+// agentConn hub 端的一个 agent 连接
+type agentConn struct {
+	conn      *websocket.Conn
+	closeOnce sync.Once
+}
+
+// close 发送关闭帧并关闭连接，可重复调用
+func (a *agentConn) close(reason closeReason) {
+	a.closeOnce.Do(func() {
+		a.conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(reason.code, reason.text), time.Now().Add(agentWriteWait))
+		a.conn.Close()
+	})
+}
+
+// HandleAgent 接收 agent 的 WebSocket 连接（仅 hub 模式）
+// agent 通过 Authorization: Bearer <cluster.token> 认证，连接后先发送 hello，之后定期发送 sample
+func HandleAgent(c *gin.Context) {
+	if settings.Mode != config.ModeHub {
+		c.JSON(http.StatusNotFound, gin.H{"detail": "this server is not running in hub mode"})
+		return
+	}
+
+	token, _ := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(settings.Token)) != 1 {
+		log.Printf("Cluster: rejected agent connection from %s: invalid token", c.ClientIP())
+		c.JSON(http.StatusUnauthorized, gin.H{"detail": "invalid cluster token"})
+		return
+	}
+
+	conn, err := agentUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("Cluster: failed to upgrade agent connection: %v", err)
+		return
+	}
+	conn.SetReadLimit(maxAgentMessage)
+	agent := &agentConn{conn: conn}
+
+	id, err := readHello(conn)
+	if err != nil {
+		log.Printf("Cluster: agent from %s: %v", c.ClientIP(), err)
+		agent.close(closeReason{websocket.ClosePolicyViolation, err.Error()})
+		return
+	}
+	if reason, ok := register(id, c.ClientIP(), agent); !ok {
+		agent.close(reason)
+		return
+	}
+	log.Printf("Cluster: node %s connected from %s", id, c.ClientIP())
+
+	err = readSamples(id, agent)
+	if unregister(id, agent) {
+		log.Printf("Cluster: node %s disconnected: %v", id, err)
+	}
+	agent.close(closeReason{websocket.CloseNormalClosure, ""})
+}
+
+// readHello 读取 hello 消息并返回节点 ID
+func readHello(conn *websocket.Conn) (string, error) {
+	conn.SetReadDeadline(time.Now().Add(helloTimeout))
+	var msg agentMessage
+	if err := conn.ReadJSON(&msg); err != nil {
+		return "", fmt.Errorf("failed to read hello: %w", err)
+	}
+	if msg.Type != messageHello {
+		return "", fmt.Errorf("expected hello, got %q", msg.Type)
+	}
+	if !validNodeID.MatchString(msg.NodeID) {
+		return "", fmt.Errorf("invalid node id %q", msg.NodeID)
+	}
+	if settings.IncludeLocal && msg.NodeID == localID {
+		return "", fmt.Errorf("node id %q is used by the hub itself", msg.NodeID)
+	}
+	return msg.NodeID, nil
+}
+
+// readSamples 读取采样直到连接断开或超过 staleAfter 没有消息
+func readSamples(id string, agent *agentConn) error {
+	staleAfter := time.Duration(settings.StaleAfter) * time.Second
+	for {
+		agent.conn.SetReadDeadline(time.Now().Add(staleAfter))
+		var msg agentMessage
+		if err := agent.conn.ReadJSON(&msg); err != nil {
+			return err
+		}
+		if msg.Type != messageSample || msg.Sample == nil {
+			continue
+		}
+
+		mu.Lock()
+		n, ok := nodes[id]
+		if !ok || n.conn != agent {
+			// 节点已被删除
+			mu.Unlock()
+			return fmt.Errorf("connection no longer active")
+		}
+		n.record(*msg.Sample, time.Now())
+		mu.Unlock()
+	}
+}
+
+// register 将连接登记为节点的当前连接
+// 节点已有在线的连接时拒绝新连接，避免持有 cluster.token 的 agent 冒用其他节点的 ID 顶替其上报；
+// 旧连接断开或超过 staleAfter 没有上报后才能重新连接。拒绝时返回关闭连接的原因和 false
+func register(id, address string, agent *agentConn) (closeReason, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	if closing {
+		return closeShutdown, false
+	}
+	if n, ok := nodes[id]; ok && n.conn != nil {
+		log.Printf("Cluster: rejected agent connection from %s: node %s is already connected from %s", address, id, n.address)
+		return closeDuplicate, false
+	}
+	n := getOrCreateLocked(id)
+	n.conn = agent
+	n.address = address
+	n.connectedAt = time.Now()
+	return closeReason{}, true
+}
+
+// unregister 连接断开时清除节点的当前连接，节点保留并标记为 stale
+// 节点已删除时返回 false
+func unregister(id string, agent *agentConn) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	n, ok := nodes[id]
+	if !ok || n.conn != agent {
+		return false
+	}
+	n.conn = nil
+	return true
+}
+
+// closing 服务正在关闭，不再接受 agent
+var closing bool
+
+// CloseAgents 断开所有 agent 并拒绝新的连接，在 http.Server.Shutdown 开始时调用
+func CloseAgents() {
+	mu.Lock()
+	closing = true
+	var conns []*agentConn
+	for _, n := range nodes {
+		if n.conn != nil {
+			conns = append(conns, n.conn)
+		}
+	}
+	mu.Unlock()
+
+	for _, conn := range conns {
+		conn.close(closeShutdown)
+	}
+}
@@ -0,0 +1,190 @@
+package cluster
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 标签选择器的运算符
+const (
+	opEquals    = "="
+	opNotEquals = "!="
+	opIn        = "in"
+	opNotIn     = "notin"
+	opExists    = "exists"
+	opNotExists = "!"
+)
+
+// requirement 选择器中的一个条件
+type requirement struct {
+	key    string
+	op     string
+	values []string
+}
+
+// Selector 标签选择器，语法与 kubectl -l 相同，多个条件以逗号分隔且需同时满足：
+// key=value、key==value、key!=value、key in (a,b)、key notin (a,b)、key（存在）、!key（不存在）
+// 零值匹配所有节点
+type Selector struct {
+	requirements []requirement
+}
+
+// ParseSelector 解析标签选择器
+func ParseSelector(s string) (Selector, error) {
+	var sel Selector
+	parts, err := splitSelector(s)
+	if err != nil {
+		return Selector{}, err
+	}
+	for _, part := range parts {
+		req, err := parseRequirement(part)
+		if err != nil {
+			return Selector{}, err
+		}
+		sel.requirements = append(sel.requirements, req)
+	}
+	return sel, nil
+}
+
+// splitSelector 按逗号拆分条件，忽略括号内的逗号
+func splitSelector(s string) ([]string, error) {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+			if depth > 1 {
+				return nil, fmt.Errorf("nested parentheses in selector %q", s)
+			}
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses in selector %q", s)
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses in selector %q", s)
+	}
+	parts = append(parts, s[start:])
+
+	result := parts[:0]
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			if len(parts) > 1 {
+				return nil, fmt.Errorf("empty requirement in selector %q", s)
+			}
+			continue
+		}
+		result = append(result, part)
+	}
+	return result, nil
+}
+
+// parseRequirement 解析单个条件
+func parseRequirement(s string) (requirement, error) {
+	if key, ok := strings.CutPrefix(s, "!"); ok {
+		key = strings.TrimSpace(key)
+		if !validKey(key) {
+			return requirement{}, fmt.Errorf("invalid label key in %q", s)
+		}
+		return requirement{key: key, op: opNotExists}, nil
+	}
+
+	if i := strings.IndexAny(s, "!="); i >= 0 {
+		key, rest := strings.TrimSpace(s[:i]), s[i:]
+		op := opEquals
+		switch {
+		case strings.HasPrefix(rest, "!="):
+			op, rest = opNotEquals, rest[2:]
+		case strings.HasPrefix(rest, "=="):
+			rest = rest[2:]
+		case strings.HasPrefix(rest, "="):
+			rest = rest[1:]
+		default:
+			return requirement{}, fmt.Errorf("invalid operator in %q", s)
+		}
+		value := strings.TrimSpace(rest)
+		if !validKey(key) {
+			return requirement{}, fmt.Errorf("invalid label key in %q", s)
+		}
+		if !validValue(value) {
+			return requirement{}, fmt.Errorf("invalid label value in %q", s)
+		}
+		return requirement{key: key, op: op, values: []string{value}}, nil
+	}
+
+	if i := strings.IndexByte(s, '('); i >= 0 {
+		fields := strings.Fields(s[:i])
+		if len(fields) != 2 || (fields[1] != opIn && fields[1] != opNotIn) || !strings.HasSuffix(s, ")") {
+			return requirement{}, fmt.Errorf("expected \"key in (values)\" or \"key notin (values)\", got %q", s)
+		}
+		if !validKey(fields[0]) {
+			return requirement{}, fmt.Errorf("invalid label key in %q", s)
+		}
+		var values []string
+		for _, v := range strings.Split(s[i+1:len(s)-1], ",") {
+			v = strings.TrimSpace(v)
+			if !validValue(v) {
+				return requirement{}, fmt.Errorf("invalid label value in %q", s)
+			}
+			values = append(values, v)
+		}
+		return requirement{key: fields[0], op: fields[1], values: values}, nil
+	}
+
+	if !validKey(s) {
+		return requirement{}, fmt.Errorf("invalid label key %q", s)
+	}
+	return requirement{key: s, op: opExists}, nil
+}
+
+// validKey / validValue 只排除与选择器语法冲突的字符，标签本身的格式由配置校验保证
+func validKey(key string) bool {
+	return key != "" && len(key) <= 253 && !strings.ContainsAny(key, " \t!=(),")
+}
+
+func validValue(value string) bool {
+	return len(value) <= 63 && !strings.ContainsAny(value, " \t!=(),")
+}
+
+// Matches 判断标签是否满足所有条件
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, req := range s.requirements {
+		value, ok := labels[req.key]
+		switch req.op {
+		case opEquals:
+			if !ok || value != req.values[0] {
+				return false
+			}
+		case opNotEquals:
+			if ok && value == req.values[0] {
+				return false
+			}
+		case opIn:
+			if !ok || !contains(req.values, value) {
+				return false
+			}
+		case opNotIn:
+			if ok && contains(req.values, value) {
+				return false
+			}
+		case opExists:
+			if !ok {
+				return false
+			}
+		case opNotExists:
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
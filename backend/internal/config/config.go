@@ -1,10 +1,11 @@
 package config
 
 import (
-	"encoding/json"
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"sync"
 )
 
 // Config 应用配置
@@ -14,16 +15,162 @@ type Config struct {
 		GithubURL string `json:"githubUrl"`
 	} `json:"app"`
 	Server struct {
-		Host         string   `json:"host"`
-		Port         int      `json:"port"`
-		CORSOrigins  []string `json:"corsOrigins"`
-		CORSMethods  []string `json:"corsMethods"`
-		PollInterval int      `json:"pollInterval"`
-		HistorySize  int      `json:"historySize"`
+		Host         string    `json:"host"`
+		Port         int       `json:"port"`
+		CORSOrigins  []string  `json:"corsOrigins"`
+		CORSMethods  []string  `json:"corsMethods"`
+		PollInterval int       `json:"pollInterval"`
+		HistorySize  int       `json:"historySize"`
+		TLS          TLSConfig `json:"tls"`
 	} `json:"server"`
+	Storage   StorageConfig   `json:"storage"`
+	Network   NetworkConfig   `json:"network"`
+	Auth      AuthConfig      `json:"auth"`
+	Audit     AuditConfig     `json:"audit"`
+	WebSocket WebSocketConfig `json:"websocket"`
+	Cluster   ClusterConfig   `json:"cluster"`
 }
 
-var globalConfig *Config
+// 集群运行模式
+const (
+	ModeStandalone = "standalone" // 单机，只展示本机
+	ModeAgent      = "agent"      // 采集本机数据并上报到 hub，不提供 Web 界面
+	ModeHub        = "hub"        // 接收多个 agent 的上报，提供集群 API 和 Web 界面
+)
+
+// ClusterConfig 多节点部署配置
+// agent 与 hub 使用相同的 token 认证，token 可通过 MLDASH_CLUSTER_TOKEN 设置
+type ClusterConfig struct {
+	Mode           string `json:"mode"`           // standalone / agent / hub
+	NodeID         string `json:"nodeId"`         // 节点 ID，默认为主机名
+	HubURL         string `json:"hubUrl"`         // agent 连接的 hub 地址，如 https://dash.example.com:8000
+	Token          string `json:"token"`          // agent 与 hub 之间的共享密钥
+	CAFile         string `json:"caFile"`         // agent 校验 hub 证书使用的 CA（自签名证书时）
+	ReportInterval int    `json:"reportInterval"` // 采样上报间隔（毫秒）
+	StaleAfter     int    `json:"staleAfter"`     // 超过该时间（秒）未收到上报的节点标记为 stale
+	HistorySize    int    `json:"historySize"`    // 每个节点保留的历史采样数量
+	IncludeLocal   bool   `json:"includeLocal"`   // hub 模式下是否同时展示 hub 所在的机器
+
+	Labels map[string]string       `json:"labels"` // 本节点的标签，如 project、rack、owner、costCenter
+	Notes  string                  `json:"notes"`  // 本节点的备注
+	Nodes  map[string]NodeMetadata `json:"nodes"`  // hub 为各节点（按 ID）设置的标签和备注，覆盖节点自己上报的
+}
+
+// NodeMetadata 节点的标签和备注
+type NodeMetadata struct {
+	Labels map[string]string `json:"labels"`
+	Notes  string            `json:"notes"`
+}
+
+// WebSocketConfig WebSocket 连接限制
+// 连接的 Origin 按 server.corsOrigins 校验
+type WebSocketConfig struct {
+	MaxConnections      int     `json:"maxConnections"`      // 最大连接数，0 表示不限制
+	MaxConnectionsPerIP int     `json:"maxConnectionsPerIp"` // 每个 IP 的最大连接数，0 表示不限制
+	MessageRate         float64 `json:"messageRate"`         // 每个连接每秒允许接收的消息数
+	MessageBurst        int     `json:"messageBurst"`        // 允许的突发消息数
+	MinInterval         int     `json:"minInterval"`         // 客户端可请求的最短推送间隔（毫秒）
+	MaxInterval         int     `json:"maxInterval"`         // 客户端可请求的最长推送间隔（毫秒）
+	Compression         bool    `json:"compression"`         // 启用 permessage-deflate 压缩
+}
+
+// TLSConfig HTTPS 配置，证书文件变更后自动重新加载
+type TLSConfig struct {
+	Enabled         bool     `json:"enabled"`
+	CertFile        string   `json:"certFile"`
+	KeyFile         string   `json:"keyFile"`
+	SelfSigned      bool     `json:"selfSigned"`      // 证书文件不存在时生成自签名证书
+	SelfSignedHosts []string `json:"selfSignedHosts"` // 自签名证书额外包含的域名或 IP
+	RedirectHTTP    bool     `json:"redirectHttp"`    // 在 httpPort 上监听并跳转到 HTTPS
+	HTTPPort        int      `json:"httpPort"`
+	HSTS            bool     `json:"hsts"`         // 发送 Strict-Transport-Security 头
+	HSTSMaxAge      int      `json:"hstsMaxAge"`   // 秒
+	ClientCAFile    string   `json:"clientCaFile"` // 用于校验客户端证书的 CA
+	ClientAuth      string   `json:"clientAuth"`   // none / optional / require
+}
+
+// AuditConfig 审计日志配置
+type AuditConfig struct {
+	Enabled    bool   `json:"enabled"`
+	Path       string `json:"path"`       // JSON Lines 文件路径
+	MaxSizeMB  int    `json:"maxSizeMb"`  // 单个文件达到该大小后轮转
+	MaxBackups int    `json:"maxBackups"` // 保留的轮转文件数量
+}
+
+// AuthConfig 认证配置
+type AuthConfig struct {
+	Enabled      bool           `json:"enabled"`
+	SessionTTL   int            `json:"sessionTtl"`   // 登录会话有效期（分钟）
+	CookieName   string         `json:"cookieName"`   // 会话 cookie 名称
+	SecureCookie bool           `json:"secureCookie"` // 仅通过 HTTPS 发送 cookie
+	Users        []UserConfig   `json:"users"`
+	Tokens       []TokenConfig  `json:"tokens"`
+	Policies     []PolicyConfig `json:"policies"`
+	OIDC         OIDCConfig     `json:"oidc"`
+}
+
+// OIDCConfig OpenID Connect 单点登录配置（授权码 + PKCE）
+type OIDCConfig struct {
+	Enabled               bool              `json:"enabled"`
+	ProviderName          string            `json:"providerName"` // 登录按钮显示的名称
+	Issuer                string            `json:"issuer"`       // 如 https://keycloak.example.com/realms/lab
+	ClientID              string            `json:"clientId"`
+	ClientSecret          string            `json:"clientSecret"`          // 公共客户端留空
+	RedirectURL           string            `json:"redirectUrl"`           // 指向 /api/auth/oidc/callback
+	PostLogoutRedirectURL string            `json:"postLogoutRedirectUrl"` // 在 provider 注销后返回的地址
+	Scopes                []string          `json:"scopes"`
+	UsernameClaim         string            `json:"usernameClaim"` // 默认 preferred_username
+	GroupsClaim           string            `json:"groupsClaim"`   // 默认 groups
+	GroupRoles            map[string]string `json:"groupRoles"`    // 组名 -> 角色
+	DefaultRole           string            `json:"defaultRole"`   // 未匹配任何组时的角色，留空则拒绝登录
+}
+
+// UserConfig 本地用户，密码使用 bcrypt 哈希保存
+type UserConfig struct {
+	Username     string `json:"username"`
+	PasswordHash string `json:"passwordHash"`
+	Role         string `json:"role"` // viewer / operator / admin
+}
+
+// TokenConfig API token，仅保存 SHA-256 哈希
+type TokenConfig struct {
+	Name      string `json:"name"`
+	TokenHash string `json:"tokenHash"`
+	User      string `json:"user"` // token 代表的用户
+}
+
+// PolicyConfig 容器操作策略，限定用户或角色可以操作的容器
+// 适用于某用户的策略中只要有一条允许即可执行；admin 不受策略限制
+type PolicyConfig struct {
+	User            string            `json:"user,omitempty"`            // 适用的用户
+	Role            string            `json:"role,omitempty"`            // 适用的角色（未指定 user 时）
+	Actions         []string          `json:"actions,omitempty"`         // 允许的操作，空或 "*" 表示全部
+	ContainerNames  []string          `json:"containerNames,omitempty"`  // 容器名通配符，支持 {user}
+	ContainerLabels map[string]string `json:"containerLabels,omitempty"` // 必须匹配的标签，值支持 {user}
+}
+
+// NetworkConfig 网络接口展示配置
+type NetworkConfig struct {
+	HideVirtual  bool `json:"hideVirtual"`  // 隐藏 veth、docker0、br-* 等虚拟接口
+	GroupVirtual bool `json:"groupVirtual"` // 将虚拟接口归入所属网桥的 members
+}
+
+// StorageConfig 目录容量统计配置
+type StorageConfig struct {
+	WatchDirs    []string `json:"watchDirs"`    // 需要统计的目录，支持 ~ 开头
+	ScanInterval int      `json:"scanInterval"` // 扫描间隔（秒）
+	TopN         int      `json:"topN"`         // 每个目录保留的最大子项数量
+	HistorySize  int      `json:"historySize"`  // 每个目录保留的历史记录数量
+}
+
+var (
+	configMu     sync.RWMutex
+	globalConfig *Config
+	// configPath 配置文件路径，由 --config 指定或在 Load 时查找
+	configPath string
+	// overrides 命令行指定的配置项（点分路径 -> 值），优先级高于环境变量
+	overrides map[string]string
+)
 
 // GetDefault 返回默认配置
 func GetDefault() *Config {
@@ -36,12 +183,13 @@ func GetDefault() *Config {
 			GithubURL: "https://github.com/dat-G/MLServer_Dash",
 		},
 		Server: struct {
-			Host         string   `json:"host"`
-			Port         int      `json:"port"`
-			CORSOrigins  []string `json:"corsOrigins"`
-			CORSMethods  []string `json:"corsMethods"`
-			PollInterval int      `json:"pollInterval"`
-			HistorySize  int      `json:"historySize"`
+			Host         string    `json:"host"`
+			Port         int       `json:"port"`
+			CORSOrigins  []string  `json:"corsOrigins"`
+			CORSMethods  []string  `json:"corsMethods"`
+			PollInterval int       `json:"pollInterval"`
+			HistorySize  int       `json:"historySize"`
+			TLS          TLSConfig `json:"tls"`
 		}{
 			Host:         "0.0.0.0",
 			Port:         8000,
@@ -49,73 +197,214 @@ func GetDefault() *Config {
 			CORSMethods:  []string{"GET", "POST", "PUT", "DELETE"},
 			PollInterval: 2000,
 			HistorySize:  30,
+			TLS: TLSConfig{
+				CertFile:   "tls/cert.pem",
+				KeyFile:    "tls/key.pem",
+				HTTPPort:   80,
+				HSTSMaxAge: 31536000,
+				ClientAuth: "none",
+			},
+		},
+		Storage: StorageConfig{
+			WatchDirs:    []string{},
+			ScanInterval: 600,
+			TopN:         10,
+			HistorySize:  48,
+		},
+		Auth: AuthConfig{
+			Enabled:    false,
+			SessionTTL: 720,
+			CookieName: "mldash_session",
+			Users:      []UserConfig{},
+			Tokens:     []TokenConfig{},
+			Policies:   []PolicyConfig{},
+			OIDC: OIDCConfig{
+				Scopes:        []string{"openid", "profile", "email", "groups"},
+				UsernameClaim: "preferred_username",
+				GroupsClaim:   "groups",
+				GroupRoles:    map[string]string{},
+			},
+		},
+		WebSocket: WebSocketConfig{
+			MaxConnections:      200,
+			MaxConnectionsPerIP: 20,
+			MessageRate:         10,
+			MessageBurst:        20,
+			MinInterval:         500,
+			MaxInterval:         60000,
+			Compression:         true,
+		},
+		Audit: AuditConfig{
+			Enabled:    true,
+			Path:       "audit.log",
+			MaxSizeMB:  10,
+			MaxBackups: 5,
+		},
+		Cluster: ClusterConfig{
+			Mode:           ModeStandalone,
+			ReportInterval: 5000,
+			StaleAfter:     30,
+			HistorySize:    360,
+			IncludeLocal:   true,
+			Labels:         map[string]string{},
+			Nodes:          map[string]NodeMetadata{},
 		},
 	}
 }
 
+// SetPath 指定配置文件路径（--config），需在 Load 之前调用
+func SetPath(path string) {
+	configPath = path
+}
+
+// Path 返回正在使用的配置文件路径
+func Path() string {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return configPath
+}
+
+// SetOverrides 设置命令行覆盖的配置项，键为 JSON 点分路径（如 server.port），需在 Load 之前调用
+func SetOverrides(values map[string]string) {
+	overrides = values
+}
+
 // Load 加载配置文件
-// 首先尝试从可执行文件同目录读取，然后尝试从当前工作目录读取
+// 未通过 SetPath 指定路径时，首先尝试从可执行文件同目录读取，然后尝试从当前工作目录读取，
 // 如果配置文件不存在，则创建默认配置文件
+// 文件中的值依次被 MLDASH_* 环境变量和命令行参数覆盖，最后校验整份配置
 func Load() (*Config, error) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
 	if globalConfig != nil {
 		return globalConfig, nil
 	}
 
-	// 获取可执行文件所在目录
-	execPath, err := os.Executable()
+	allowMissing := configPath == ""
+	if configPath == "" {
+		path, err := findConfig()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			// 文件不存在，创建默认配置
+			log.Printf("Config file not found, creating default config: %s", path)
+			if err := saveConfig(path, GetDefault()); err != nil {
+				log.Printf("Warning: failed to save default config: %v", err)
+			}
+		}
+		configPath = path
+	}
+
+	cfg, err := readConfig(configPath, allowMissing)
 	if err != nil {
 		return nil, err
 	}
-	execDir := filepath.Dir(execPath)
-	configPath := filepath.Join(execDir, "config.json")
 
-	// 如果可执行文件目录找不到，尝试当前工作目录
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		configPath = "config.json"
-	}
+	globalConfig = cfg
+	return globalConfig, nil
+}
 
-	// 尝试读取配置文件
-	data, err := os.ReadFile(configPath)
+// findConfig 依次在可执行文件目录和当前工作目录查找 config.json / config.yaml / config.yml / config.toml
+// 都不存在时返回当前工作目录下的 config.json
+func findConfig() (string, error) {
+	// 获取可执行文件所在目录
+	execPath, err := os.Executable()
 	if err != nil {
-		// 文件不存在，创建默认配置
-		if os.IsNotExist(err) {
-			log.Printf("Config file not found, creating default config: %s", configPath)
-			cfg := GetDefault()
+		return "", err
+	}
 
-			// 写入默认配置文件
-			if err := saveConfig(configPath, cfg); err != nil {
-				log.Printf("Warning: failed to save default config: %v", err)
+	for _, dir := range []string{filepath.Dir(execPath), "."} {
+		for _, name := range configNames {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				if dir == "." {
+					return name, nil
+				}
+				return path, nil
 			}
-
-			globalConfig = cfg
-			return globalConfig, nil
 		}
-		return nil, err
 	}
+	return "config.json", nil
+}
 
-	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return nil, err
+// readConfig 读取并解析配置文件，应用环境变量和命令行覆盖后校验
+// allowMissing 为 true 时文件不存在则使用默认配置（默认配置文件写入失败的情况）
+func readConfig(path string, allowMissing bool) (*Config, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if !(allowMissing && os.IsNotExist(err)) {
+			return nil, err
+		}
+		raw = []byte("{}")
 	}
 
-	globalConfig = &cfg
-	return globalConfig, nil
+	doc, err := parseDocument(path, raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	cfg, err := doc.build()
+	if err != nil {
+		if verr, ok := err.(*ValidationError); ok {
+			verr.File = path
+			return nil, verr
+		}
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
 }
 
-// saveConfig 保存配置到文件
+// saveConfig 保存配置到文件，格式由扩展名决定
+// 先写入同目录的临时文件再重命名，写入中途失败或进程退出不会留下不完整的配置文件
 func saveConfig(path string, cfg *Config) error {
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	data, err := encodeConfig(path, cfg)
+	if err != nil {
+		return err
+	}
+
+	// 配置文件是符号链接时（如 Kubernetes ConfigMap）替换链接指向的文件
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	// 配置文件包含密码哈希、token 哈希和 client secret，新建时只允许属主读写，已有文件保留原权限
+	mode := os.FileMode(0600)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0644)
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
 }
 
-// Get 获取配置
+// Get 获取当前配置，热加载后返回新的配置
+// 返回的配置不能修改，需要修改时先复制
 func Get() *Config {
-	if globalConfig == nil {
-		cfg, _ := Load()
-		return cfg
+	configMu.RLock()
+	cfg := globalConfig
+	configMu.RUnlock()
+
+	if cfg == nil {
+		cfg, _ = Load()
 	}
-	return globalConfig
+	return cfg
 }
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// envPrefix 环境变量前缀
+// 配置项 server.pollInterval 对应 MLDASH_SERVER_POLL_INTERVAL，auth.oidc.clientSecret 对应 MLDASH_AUTH_OIDC_CLIENT_SECRET
+const envPrefix = "MLDASH_"
+
+// field 一个可以通过环境变量或命令行覆盖的配置项
+type field struct {
+	path  string // JSON 点分路径，如 server.pollInterval
+	value reflect.Value
+}
+
+// fields 列出 cfg 中所有标量、字符串数组和字符串映射类型的配置项
+// users、tokens、policies 等结构体数组无法用单个值表示，不支持覆盖
+func fields(cfg *Config) []field {
+	var result []field
+	var walk func(prefix string, v reflect.Value)
+	walk = func(prefix string, v reflect.Value) {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			path := name
+			if prefix != "" {
+				path = prefix + "." + name
+			}
+
+			fv := v.Field(i)
+			switch fv.Kind() {
+			case reflect.Struct:
+				walk(path, fv)
+			case reflect.String, reflect.Bool, reflect.Int, reflect.Float64:
+				result = append(result, field{path: path, value: fv})
+			case reflect.Slice:
+				if fv.Type().Elem().Kind() == reflect.String {
+					result = append(result, field{path: path, value: fv})
+				}
+			case reflect.Map:
+				if fv.Type().Key().Kind() == reflect.String && fv.Type().Elem().Kind() == reflect.String {
+					result = append(result, field{path: path, value: fv})
+				}
+			}
+		}
+	}
+	walk("", reflect.ValueOf(cfg).Elem())
+	return result
+}
+
+// envName 返回配置项对应的环境变量名
+func envName(path string) string {
+	var b strings.Builder
+	b.WriteString(envPrefix)
+	for i, part := range strings.Split(path, ".") {
+		if i > 0 {
+			b.WriteByte('_')
+		}
+		runes := []rune(part)
+		for j, r := range runes {
+			// 小写或数字后的大写字母开始一个新单词：corsOrigins -> CORS_ORIGINS
+			if j > 0 && unicode.IsUpper(r) && !unicode.IsUpper(runes[j-1]) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	return b.String()
+}
+
+// applyEnv 使用 MLDASH_* 环境变量覆盖配置
+func applyEnv(cfg *Config) error {
+	for _, f := range fields(cfg) {
+		name := envName(f.path)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setValue(f.value, value); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// applyOverrides 使用命令行参数覆盖配置，路径不区分大小写
+func applyOverrides(cfg *Config, values map[string]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	byPath := make(map[string]field)
+	for _, f := range fields(cfg) {
+		byPath[strings.ToLower(f.path)] = f
+	}
+	for path, value := range values {
+		f, ok := byPath[strings.ToLower(path)]
+		if !ok {
+			return fmt.Errorf("--set %s: unknown or unsupported config key", path)
+		}
+		if err := setValue(f.value, value); err != nil {
+			return fmt.Errorf("--set %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// setValue 将字符串解析为字段类型后赋值
+// 数组以逗号分隔，映射以 key=value 逗号分隔，空字符串表示清空
+func setValue(v reflect.Value, raw string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		v.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		v.SetInt(int64(n))
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		items := []string{}
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	case reflect.Map:
+		m := map[string]string{}
+		for _, pair := range strings.Split(raw, ",") {
+			if pair = strings.TrimSpace(pair); pair == "" {
+				continue
+			}
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				return fmt.Errorf("invalid key=value pair %q", pair)
+			}
+			m[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+		v.Set(reflect.ValueOf(m))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,513 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// configNames 按顺序查找的配置文件名
+var configNames = []string{"config.json", "config.yaml", "config.yml", "config.toml"}
+
+// Position 配置项在文件中的位置
+type Position struct {
+	Line   int
+	Column int
+}
+
+// document 解析后的配置文件
+// YAML 和 TOML 先转换为 JSON，再按 json 标签解析，字段名与 config.json 完全一致
+type document struct {
+	data      []byte              // JSON 内容
+	positions map[string]Position // 配置项路径（如 auth.users[0].role）-> 位置
+}
+
+// parseDocument 按扩展名解析配置文件，语法错误带有行号
+func parseDocument(path string, raw []byte) (*document, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return parseYAML(raw)
+	case ".toml":
+		return parseTOML(raw)
+	default:
+		return parseJSON(raw)
+	}
+}
+
+// parseJSON 校验 JSON 语法并记录每个键的位置
+func parseJSON(raw []byte) (*document, error) {
+	doc := &document{data: raw, positions: make(map[string]Position)}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+
+	var walk func(path string) error
+	walk = func(path string) error {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'):
+			for dec.More() {
+				offset := skipSeparators(raw, int(dec.InputOffset()))
+				keyTok, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				key := joinPath(path, keyTok.(string))
+				doc.positions[key] = offsetPosition(raw, offset)
+				if err := walk(key); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+		case json.Delim('['):
+			for i := 0; dec.More(); i++ {
+				item := fmt.Sprintf("%s[%d]", path, i)
+				doc.positions[item] = offsetPosition(raw, skipSeparators(raw, int(dec.InputOffset())))
+				if err := walk(item); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+		}
+		return err
+	}
+
+	if err := walk(""); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			pos := offsetPosition(raw, int(syntaxErr.Offset))
+			return nil, fmt.Errorf("line %d, column %d: %v", pos.Line, pos.Column, err)
+		}
+		return nil, err
+	}
+	return doc, nil
+}
+
+// skipSeparators 跳过空白、逗号和冒号，返回下一个 token 的起始偏移
+func skipSeparators(raw []byte, offset int) int {
+	for offset < len(raw) {
+		switch raw[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+// offsetPosition 将字节偏移转换为行列号（从 1 开始）
+func offsetPosition(raw []byte, offset int) Position {
+	if offset > len(raw) {
+		offset = len(raw)
+	}
+	before := raw[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := offset - bytes.LastIndexByte(before, '\n')
+	return Position{Line: line, Column: column}
+}
+
+// parseYAML 解析 YAML，转换为 JSON 并记录每个键的位置
+func parseYAML(raw []byte) (*document, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(raw, &root); err != nil {
+		return nil, err
+	}
+
+	doc := &document{positions: make(map[string]Position)}
+	var convert func(node *yaml.Node, path string) (interface{}, error)
+	convert = func(node *yaml.Node, path string) (interface{}, error) {
+		switch node.Kind {
+		case yaml.DocumentNode:
+			if len(node.Content) == 0 {
+				return map[string]interface{}{}, nil
+			}
+			return convert(node.Content[0], path)
+		case yaml.AliasNode:
+			return convert(node.Alias, path)
+		case yaml.MappingNode:
+			m := make(map[string]interface{}, len(node.Content)/2)
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				keyNode, valueNode := node.Content[i], node.Content[i+1]
+				key := joinPath(path, keyNode.Value)
+				doc.positions[key] = Position{Line: keyNode.Line, Column: keyNode.Column}
+				value, err := convert(valueNode, key)
+				if err != nil {
+					return nil, err
+				}
+				m[keyNode.Value] = value
+			}
+			return m, nil
+		case yaml.SequenceNode:
+			items := make([]interface{}, 0, len(node.Content))
+			for i, itemNode := range node.Content {
+				item := fmt.Sprintf("%s[%d]", path, i)
+				doc.positions[item] = Position{Line: itemNode.Line, Column: itemNode.Column}
+				value, err := convert(itemNode, item)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, value)
+			}
+			return items, nil
+		default:
+			var value interface{}
+			if err := node.Decode(&value); err != nil {
+				return nil, fmt.Errorf("line %d, column %d: %v", node.Line, node.Column, err)
+			}
+			return value, nil
+		}
+	}
+
+	value, err := convert(&root, "")
+	if err != nil {
+		return nil, err
+	}
+	if doc.data, err = json.Marshal(value); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// parseTOML 解析 TOML，转换为 JSON 并按行记录键和表的位置
+func parseTOML(raw []byte) (*document, error) {
+	var value map[string]interface{}
+	if err := toml.Unmarshal(raw, &value); err != nil {
+		var decodeErr *toml.DecodeError
+		if errors.As(err, &decodeErr) {
+			row, column := decodeErr.Position()
+			return nil, fmt.Errorf("line %d, column %d: %v", row, column, err)
+		}
+		return nil, err
+	}
+
+	doc := &document{positions: tomlPositions(raw)}
+	var err error
+	if doc.data, err = json.Marshal(value); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// tomlPositions 逐行扫描 [table]、[[array]] 和 key = value，覆盖常见的书写方式
+func tomlPositions(raw []byte) map[string]Position {
+	positions := make(map[string]Position)
+	arrays := make(map[string]int)
+	table := ""
+	for i, line := range strings.Split(string(raw), "\n") {
+		trimmed := strings.TrimSpace(line)
+		column := len(line) - len(strings.TrimLeft(line, " \t")) + 1
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+		case strings.HasPrefix(trimmed, "[["):
+			name, _, _ := strings.Cut(strings.TrimPrefix(trimmed, "[["), "]]")
+			name = strings.TrimSpace(name)
+			table = fmt.Sprintf("%s[%d]", name, arrays[name])
+			arrays[name]++
+			positions[table] = Position{Line: i + 1, Column: column}
+		case strings.HasPrefix(trimmed, "["):
+			name, _, _ := strings.Cut(strings.TrimPrefix(trimmed, "["), "]")
+			table = strings.TrimSpace(name)
+			positions[table] = Position{Line: i + 1, Column: column}
+		default:
+			if key, _, ok := strings.Cut(trimmed, "="); ok {
+				key = strings.Trim(strings.TrimSpace(key), `"'`)
+				positions[joinPath(table, key)] = Position{Line: i + 1, Column: column}
+			}
+		}
+	}
+	return positions
+}
+
+// encodeConfig 按扩展名将配置编码为 JSON、YAML 或 TOML
+func encodeConfig(path string, cfg *Config) ([]byte, error) {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		// JSON 是合法的 YAML，解析为节点树后改为块格式输出，保持字段顺序
+		var root yaml.Node
+		if err := yaml.Unmarshal(data, &root); err != nil {
+			return nil, err
+		}
+		blockStyle(&root)
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(&root); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case ".toml":
+		var value map[string]interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+		return toml.Marshal(integers(value))
+	default:
+		return data, nil
+	}
+}
+
+// blockStyle 清除节点的 flow 样式和 JSON 字符串的引号，输出为常规的 YAML 写法
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// integers 将 JSON 解析出的整数值由 float64 转回 int64，否则 TOML 中会写成 8000.0
+func integers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = integers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = integers(item)
+		}
+	case float64:
+		if v == float64(int64(v)) {
+			return int64(v)
+		}
+	}
+	return value
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// decode 在默认配置基础上解析文档
+// 未知的键和类型不匹配的值作为 FieldError 返回，便于与 Validate 的结果一起报告；error 仅表示无法解析
+func (doc *document) decode() (*Config, []FieldError, error) {
+	var generic interface{}
+	if err := json.Unmarshal(doc.data, &generic); err != nil {
+		return nil, nil, err
+	}
+	if _, ok := generic.(map[string]interface{}); !ok {
+		return nil, nil, errors.New("config must be an object")
+	}
+
+	var errs []FieldError
+	checkKeys(generic, reflect.TypeOf(Config{}), "", &errs)
+
+	// 在默认配置基础上解析，旧配置文件缺少的配置段保持默认值
+	// 类型不匹配时 encoding/json 会继续解析其余字段，只返回第一个错误
+	cfg := GetDefault()
+	if err := json.Unmarshal(doc.data, cfg); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			return nil, nil, err
+		}
+		errs = append(errs, FieldError{
+			Path:    typeErr.Field,
+			Message: fmt.Sprintf("expected %s, got %s", typeName(typeErr.Type), typeErr.Value),
+		})
+	}
+	return cfg, errs, nil
+}
+
+// build 解析文档，应用环境变量和命令行覆盖后校验，即服务实际使用的配置
+func (doc *document) build() (*Config, error) {
+	cfg, errs, err := doc.decode()
+	if err != nil {
+		return nil, err
+	}
+	if err := applyEnv(cfg); err != nil {
+		return nil, err
+	}
+	if err := applyOverrides(cfg, overrides); err != nil {
+		return nil, err
+	}
+	if err := doc.check(cfg, errs); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// check 合并解析错误和 Validate 的结果，返回带行号的 *ValidationError，没有错误时返回 nil
+func (doc *document) check(cfg *Config, errs []FieldError) error {
+	if err := cfg.Validate(); err != nil {
+		errs = append(errs, err.(*ValidationError).Errors...)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return doc.locate(&ValidationError{Errors: errs})
+}
+
+// checkKeys 检查文档中是否有配置结构中不存在的键（通常是拼写错误）
+// 与 encoding/json 一致，键名不区分大小写
+func checkKeys(value interface{}, t reflect.Type, path string, errs *[]FieldError) {
+	switch t.Kind() {
+	case reflect.Ptr:
+		checkKeys(value, t.Elem(), path, errs)
+	case reflect.Struct:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		keys := make([]string, 0, len(m))
+		for key := range m {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			// 根对象允许 $schema，用于编辑器校验
+			if path == "" && key == "$schema" {
+				continue
+			}
+			field, ok := fieldByKey(t, key)
+			if !ok {
+				msg := "unknown key"
+				if suggestion := suggestKey(t, key); suggestion != "" {
+					msg += fmt.Sprintf(" (did you mean %q?)", suggestion)
+				}
+				*errs = append(*errs, FieldError{Path: joinPath(path, key), Message: msg})
+				continue
+			}
+			checkKeys(m[key], field.Type, joinPath(path, key), errs)
+		}
+	case reflect.Slice:
+		items, ok := value.([]interface{})
+		if !ok {
+			return
+		}
+		for i, item := range items {
+			checkKeys(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+// fieldByKey 按 JSON 名称查找字段，不区分大小写
+func fieldByKey(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if strings.EqualFold(name, key) {
+			return t.Field(i), true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// suggestKey 为拼错的键寻找编辑距离最近的字段名
+func suggestKey(t reflect.Type, key string) string {
+	best, bestDist := "", 3
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if d := editDistance(strings.ToLower(name), strings.ToLower(key)); d < bestDist {
+			best, bestDist = name, d
+		}
+	}
+	return best
+}
+
+// editDistance Levenshtein 距离
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// typeName 以配置文件中的写法描述 Go 类型
+func typeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int64, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.String:
+		return "string"
+	case reflect.Slice:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return t.String()
+}
+
+// locate 为校验错误补充行列号：先按完整路径查找，找不到时逐级退回到上层键
+func (doc *document) locate(verr *ValidationError) *ValidationError {
+	for i := range verr.Errors {
+		path := verr.Errors[i].Path
+		for path != "" {
+			if pos, ok := doc.positions[path]; ok {
+				verr.Errors[i].Line, verr.Errors[i].Column = pos.Line, pos.Column
+				break
+			}
+			if j := strings.LastIndexAny(path, ".["); j >= 0 {
+				path = path[:j]
+			} else {
+				path = ""
+			}
+		}
+	}
+	sort.SliceStable(verr.Errors, func(a, b int) bool {
+		return verr.Errors[a].Line < verr.Errors[b].Line
+	})
+	return verr
+}
+
+// ValidateFile 只校验配置文件本身（不应用环境变量和命令行覆盖），用于 validate-config 子命令
+// path 为空时按 Load 的规则查找配置文件
+// 返回的错误为 *ValidationError 时其中每一项都带有行号，其他错误为读取或语法错误
+func ValidateFile(path string) (string, error) {
+	if path == "" {
+		found, err := findConfig()
+		if err != nil {
+			return "", err
+		}
+		path = found
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return path, err
+	}
+	doc, err := parseDocument(path, raw)
+	if err != nil {
+		return path, err
+	}
+	cfg, errs, err := doc.decode()
+	if err != nil {
+		return path, err
+	}
+	return path, doc.check(cfg, errs)
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// schemaConstraints 附加到 JSON Schema 的取值约束，与 Validate 中的规则一致
+// 数组元素的路径以 [] 结尾
+var schemaConstraints = map[string]map[string]interface{}{
+	"server.port":                   {"minimum": 1, "maximum": 65535},
+	"server.pollInterval":           {"minimum": 100},
+	"server.historySize":            {"minimum": 1},
+	"server.corsOrigins[]":          {"pattern": `^(\*|https?://.+)$`},
+	"server.corsMethods[]":          {"enum": []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}},
+	"server.tls.httpPort":           {"minimum": 1, "maximum": 65535},
+	"server.tls.hstsMaxAge":         {"minimum": 0},
+	"server.tls.clientAuth":         {"enum": []string{"", "none", "optional", "require"}},
+	"storage.scanInterval":          {"minimum": 0},
+	"storage.topN":                  {"minimum": 0},
+	"storage.historySize":           {"minimum": 0},
+	"auth.sessionTtl":               {"minimum": 0},
+	"auth.users[].role":             {"enum": []string{"", "viewer", "operator", "admin"}},
+	"auth.tokens[].tokenHash":       {"pattern": "^[0-9a-fA-F]{64}$"},
+	"auth.policies[].role":          {"enum": []string{"", "viewer", "operator", "admin"}},
+	"auth.oidc.defaultRole":         {"enum": []string{"", "viewer", "operator", "admin"}},
+	"audit.maxSizeMb":               {"minimum": 0},
+	"audit.maxBackups":              {"minimum": 0},
+	"websocket.maxConnections":      {"minimum": 0},
+	"websocket.maxConnectionsPerIp": {"minimum": 0},
+	"websocket.messageRate":         {"minimum": 0},
+	"websocket.messageBurst":        {"minimum": 0},
+	"websocket.minInterval":         {"minimum": 100},
+	"websocket.maxInterval":         {"minimum": 100},
+	"cluster.mode":                  {"enum": []string{"", "standalone", "agent", "hub"}},
+	"cluster.nodeId":                {"pattern": "^([A-Za-z0-9][A-Za-z0-9._-]{0,62})?$"},
+	"cluster.hubUrl":                {"pattern": "^((https?|wss?)://.+)?$"},
+	"cluster.reportInterval":        {"minimum": 100},
+	"cluster.staleAfter":            {"minimum": 1},
+	"cluster.historySize":           {"minimum": 1},
+	"cluster.labels[]":              {"pattern": labelValue.String()},
+	"cluster.nodes[].labels[]":      {"pattern": labelValue.String()},
+}
+
+// Schema 生成配置文件的 JSON Schema（draft 2020-12），默认值取自 GetDefault
+func Schema() ([]byte, error) {
+	root := schemaFor(reflect.TypeOf(Config{}), reflect.ValueOf(GetDefault()).Elem(), "")
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["title"] = "MLServer_Dash config"
+	root["properties"].(map[string]interface{})["$schema"] = map[string]interface{}{"type": "string"}
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// schemaFor 生成类型 t 的 schema，def 为默认值（无默认值时为零 Value）
+func schemaFor(t reflect.Type, def reflect.Value, path string) map[string]interface{} {
+	s := make(map[string]interface{})
+	switch t.Kind() {
+	case reflect.Struct:
+		props := make(map[string]interface{})
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			var fieldDef reflect.Value
+			if def.IsValid() {
+				fieldDef = def.Field(i)
+			}
+			props[name] = schemaFor(t.Field(i).Type, fieldDef, joinPath(path, name))
+		}
+		s["type"] = "object"
+		s["properties"] = props
+		s["additionalProperties"] = false
+	case reflect.Slice:
+		s["type"] = "array"
+		s["items"] = schemaFor(t.Elem(), reflect.Value{}, path+"[]")
+	case reflect.Map:
+		s["type"] = "object"
+		s["additionalProperties"] = schemaFor(t.Elem(), reflect.Value{}, path+"[]")
+	case reflect.String:
+		s["type"] = "string"
+	case reflect.Bool:
+		s["type"] = "boolean"
+	case reflect.Int:
+		s["type"] = "integer"
+	case reflect.Float64:
+		s["type"] = "number"
+	}
+
+	if def.IsValid() && t.Kind() != reflect.Struct && !((t.Kind() == reflect.Slice || t.Kind() == reflect.Map) && def.IsNil()) {
+		s["default"] = def.Interface()
+	}
+	for k, v := range schemaConstraints[path] {
+		s[k] = v
+	}
+	return s
+}
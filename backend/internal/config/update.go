@@ -0,0 +1,198 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidPatch 修改内容不是合法的 JSON 对象或包含不允许修改的配置项
+var ErrInvalidPatch = errors.New("invalid config patch")
+
+// secretPaths 不通过 API 返回、也不允许通过 API 修改的配置项
+var secretPaths = []string{
+	"auth.users",
+	"auth.tokens",
+	"auth.oidc.clientSecret",
+	"cluster.token",
+}
+
+// updateMu 串行化 API 修改和热加载，避免并发的读取-合并-写入互相覆盖
+var updateMu sync.Mutex
+
+// UpdateResult 通过 API 修改配置的结果
+type UpdateResult struct {
+	Config          *Config  // 生效的配置（已应用环境变量和命令行覆盖）
+	Changed         []string // 配置文件中发生变化的配置项
+	RestartRequired []string // 修改了但需要重启才能生效的配置项
+}
+
+// Public 返回去除密码哈希、token 哈希和 OIDC client secret 后的配置
+func Public(cfg *Config) map[string]interface{} {
+	data, _ := json.Marshal(cfg)
+	var m map[string]interface{}
+	_ = json.Unmarshal(data, &m)
+
+	for _, path := range secretPaths {
+		parts := strings.Split(path, ".")
+		parent := m
+		for _, part := range parts[:len(parts)-1] {
+			parent, _ = parent[part].(map[string]interface{})
+		}
+		delete(parent, parts[len(parts)-1])
+	}
+	return m
+}
+
+// Update 将 patch（JSON Merge Patch，RFC 7386）合并到配置文件，校验通过后保存并立即应用
+// 对象按键合并，数组和其他值整体替换，null 表示恢复默认值
+// 合并基于配置文件的内容，环境变量和命令行覆盖不会被写入文件，并且仍然优先
+// 返回的错误为 *ValidationError 时表示合并后的配置无效，配置文件不会被修改
+func Update(patch []byte) (*UpdateResult, error) {
+	var p interface{}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	pm, ok := p.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: must be a JSON object", ErrInvalidPatch)
+	}
+	if key, secret := secretKey(pm); key != "" {
+		if strings.EqualFold(key, secret) {
+			return nil, fmt.Errorf("%w: %s cannot be changed through the API", ErrInvalidPatch, secret)
+		}
+		return nil, fmt.Errorf("%w: %s cannot be replaced or removed through the API because it contains %s", ErrInvalidPatch, key, secret)
+	}
+
+	updateMu.Lock()
+	defer updateMu.Unlock()
+
+	path := Path()
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		raw = []byte("{}")
+	} else if err != nil {
+		return nil, err
+	}
+	doc, err := parseDocument(path, raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	var current map[string]interface{}
+	if err := json.Unmarshal(doc.data, &current); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	data, err := json.Marshal(mergePatch(current, pm))
+	if err != nil {
+		return nil, err
+	}
+	patched := &document{data: data}
+	cfg, err := patched.build()
+	if err != nil {
+		if _, ok := err.(*ValidationError); ok {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	// 写入文件的是不含环境变量和命令行覆盖的配置
+	oldFile, _, _ := doc.decode()
+	newFile, _, _ := patched.decode()
+	if err := saveConfig(path, newFile); err != nil {
+		return nil, err
+	}
+	// 记录新文件的 stamp，Watch 不再重复加载
+	fileChanged(path)
+	log.Printf("Config: saved %s", path)
+
+	changed := []string{}
+	if oldFile != nil {
+		diffPaths(Public(oldFile), Public(newFile), "", &changed)
+	}
+	sort.Strings(changed)
+	restart := Apply(cfg)
+	if restart == nil {
+		restart = []string{}
+	}
+	return &UpdateResult{Config: cfg, Changed: changed, RestartRequired: restart}, nil
+}
+
+// secretKey 返回 patch 中会修改 secretPaths 配置项的键路径，以及被修改的配置项
+// 直接修改配置项，或将其上级对象设为 null 或非对象的值（合并时会删除或替换整个对象）都算修改
+func secretKey(patch map[string]interface{}) (key, secret string) {
+	for _, path := range secretPaths {
+		if key := touchesPath(patch, strings.Split(path, "."), ""); key != "" {
+			return key, path
+		}
+	}
+	return "", ""
+}
+
+// touchesPath 检查 patch 是否修改 parts 指向的配置项，返回 patch 中对应的键路径
+// 键不区分大小写，与 encoding/json 解析配置时一致；大小写不同的重复键逐个检查
+func touchesPath(patch map[string]interface{}, parts []string, prefix string) string {
+	for k, v := range patch {
+		if !strings.EqualFold(k, parts[0]) {
+			continue
+		}
+		key := joinPath(prefix, k)
+		if len(parts) == 1 {
+			return key
+		}
+		child, ok := v.(map[string]interface{})
+		if !ok {
+			return key
+		}
+		if key := touchesPath(child, parts[1:], key); key != "" {
+			return key
+		}
+	}
+	return ""
+}
+
+// mergePatch 按 RFC 7386 将 patch 合并到 target
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	pm, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	tm, ok := target.(map[string]interface{})
+	if !ok {
+		tm = make(map[string]interface{})
+	}
+	for key, value := range pm {
+		if value == nil {
+			delete(tm, key)
+			continue
+		}
+		tm[key] = mergePatch(tm[key], value)
+	}
+	return tm
+}
+
+// diffPaths 列出两份配置中取值不同的配置项，数组作为整体比较
+func diffPaths(old, new interface{}, path string, out *[]string) {
+	om, ok1 := old.(map[string]interface{})
+	nm, ok2 := new.(map[string]interface{})
+	if !ok1 || !ok2 {
+		if !reflect.DeepEqual(old, new) {
+			*out = append(*out, path)
+		}
+		return
+	}
+	for key, value := range nm {
+		diffPaths(om[key], value, joinPath(path, key), out)
+	}
+	for key, value := range om {
+		if _, ok := nm[key]; !ok {
+			diffPaths(value, nil, joinPath(path, key), out)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSecretKey(t *testing.T) {
+	tests := []struct {
+		patch  string
+		key    string
+		secret string
+	}{
+		{`{"auth": {"users": []}}`, "auth.users", "auth.users"},
+		{`{"AUTH": {"Tokens": null}}`, "AUTH.Tokens", "auth.tokens"},
+		{`{"auth": {"oidc": {"clientSecret": "x"}}}`, "auth.oidc.clientSecret", "auth.oidc.clientSecret"},
+		{`{"cluster": {"token": "x"}}`, "cluster.token", "cluster.token"},
+		// 上级对象设为 null 或非对象的值会删除或替换其中的敏感配置项
+		{`{"auth": null}`, "auth", "auth.users"},
+		{`{"auth": "x"}`, "auth", "auth.users"},
+		{`{"auth": [1]}`, "auth", "auth.users"},
+		{`{"auth": {"oidc": null}}`, "auth.oidc", "auth.oidc.clientSecret"},
+		{`{"cluster": null}`, "cluster", "cluster.token"},
+		{`{"Cluster": 1}`, "Cluster", "cluster.token"},
+		// 允许的修改
+		{`{"auth": {"enabled": true, "oidc": {"issuer": "https://idp"}}}`, "", ""},
+		{`{"cluster": {"mode": "hub", "labels": null}}`, "", ""},
+		{`{"server": null, "audit": {"enabled": false}}`, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.patch, func(t *testing.T) {
+			var patch map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.patch), &patch); err != nil {
+				t.Fatal(err)
+			}
+			key, secret := secretKey(patch)
+			if key != tt.key || secret != tt.secret {
+				t.Errorf("secretKey() = %q, %q, want %q, %q", key, secret, tt.key, tt.secret)
+			}
+		})
+	}
+}
+
+// useConfigFile 将配置文件指向临时文件，测试结束后恢复
+func useConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	configMu.Lock()
+	oldPath, oldConfig := configPath, globalConfig
+	configPath, globalConfig = path, nil
+	configMu.Unlock()
+	t.Cleanup(func() {
+		configMu.Lock()
+		configPath, globalConfig = oldPath, oldConfig
+		configMu.Unlock()
+	})
+	return path
+}
+
+func TestUpdateKeepsSecrets(t *testing.T) {
+	const original = `{
+  "auth": {"enabled": true, "users": [{"username": "alice", "passwordHash": "hash", "role": "admin"}]},
+  "cluster": {"mode": "standalone", "token": "cluster-secret"}
+}`
+	path := useConfigFile(t, original)
+
+	for _, patch := range []string{
+		`{"auth": null}`,
+		`{"auth": {"users": null}}`,
+		`{"auth": {"oidc": null}}`,
+		`{"cluster": null}`,
+		`{"cluster": "standalone"}`,
+	} {
+		if _, err := Update([]byte(patch)); !errors.Is(err, ErrInvalidPatch) {
+			t.Errorf("Update(%s) error = %v, want ErrInvalidPatch", patch, err)
+		}
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Fatalf("rejected patches modified the config file:\n%s", data)
+	}
+
+	// 修改同一对象中的其他配置项不影响敏感配置项
+	result, err := Update([]byte(`{"auth": {"sessionTtl": 60}, "cluster": {"labels": {"rack": "r1"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if users := result.Config.Auth.Users; len(users) != 1 || users[0].PasswordHash != "hash" {
+		t.Errorf("auth.users after update = %+v", users)
+	}
+	if token := result.Config.Cluster.Token; token != "cluster-secret" {
+		t.Errorf("cluster.token after update = %q", token)
+	}
+}
+
+func TestSaveConfigMode(t *testing.T) {
+	dir := t.TempDir()
+
+	// 新建的配置文件只允许属主读写
+	path := filepath.Join(dir, "new.json")
+	if err := saveConfig(path, &Config{}); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("mode of new config file = %v, %v, want 0600", info.Mode().Perm(), err)
+	}
+
+	// 已有文件保留原权限
+	path = filepath.Join(dir, "existing.json")
+	if err := os.WriteFile(path, []byte(`{}`), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, 0o640); err != nil {
+		t.Fatal(err)
+	}
+	if err := saveConfig(path, &Config{}); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o640 {
+		t.Errorf("mode of existing config file = %v, %v, want 0640", info.Mode().Perm(), err)
+	}
+}
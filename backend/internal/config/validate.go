@@ -0,0 +1,257 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FieldError 单个配置项的校验错误
+type FieldError struct {
+	Path    string `json:"path"` // JSON 点分路径，如 server.pollInterval
+	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"` // 配置文件中的行号，未知时为 0
+	Column  int    `json:"column,omitempty"`
+}
+
+func (e FieldError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s: %s", e.Line, e.Path, e.Message)
+	}
+	return e.Path + ": " + e.Message
+}
+
+// ValidationError 配置校验失败，包含所有出错的配置项
+type ValidationError struct {
+	File   string
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Errors)+1)
+	if e.File != "" {
+		lines = append(lines, "invalid config in "+e.File+":")
+	} else {
+		lines = append(lines, "invalid config:")
+	}
+	for _, fe := range e.Errors {
+		lines = append(lines, "  "+fe.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// validRoles 用户和 OIDC 组可以使用的角色
+var validRoles = map[string]bool{"viewer": true, "operator": true, "admin": true}
+
+// validMethods CORS 允许配置的 HTTP 方法
+var validMethods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true, "HEAD": true, "OPTIONS": true,
+}
+
+// sha256Hex token 哈希格式
+var sha256Hex = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// hubURL agent 连接的 hub 地址格式
+var hubURL = regexp.MustCompile(`^(https?|wss?)://.+`)
+
+// nodeID 节点 ID 格式，与 hub 接受的 ID 一致
+var nodeID = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,62}$`)
+
+// labelKey / labelValue 标签键和值的格式，键可以带 / 分隔的前缀
+var (
+	labelKey   = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9.-]*/)?[A-Za-z0-9]([A-Za-z0-9._-]{0,61}[A-Za-z0-9])?$`)
+	labelValue = regexp.MustCompile(`^([A-Za-z0-9]([A-Za-z0-9._-]{0,61}[A-Za-z0-9])?)?$`)
+)
+
+// validator 收集校验错误
+type validator struct {
+	errs []FieldError
+}
+
+func (v *validator) add(path, format string, args ...interface{}) {
+	v.errs = append(v.errs, FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) min(path string, value, min int) {
+	if value < min {
+		v.add(path, "must be at least %d, got %d", min, value)
+	}
+}
+
+func (v *validator) port(path string, value int) {
+	if value < 1 || value > 65535 {
+		v.add(path, "must be a port between 1 and 65535, got %d", value)
+	}
+}
+
+// labels 校验标签，格式与 Kubernetes 标签相同，保证可以在标签选择器中使用
+func (v *validator) labels(path string, labels map[string]string) {
+	for key, value := range labels {
+		if !labelKey.MatchString(key) {
+			v.add(path+"."+key, "invalid label key %q", key)
+		}
+		if !labelValue.MatchString(value) {
+			v.add(path+"."+key, "invalid label value %q (letters, digits, '.', '_' and '-', at most 63 characters)", value)
+		}
+	}
+}
+
+// Validate 校验配置，返回的错误为 *ValidationError
+// 零值或负数的间隔会导致 ticker panic，必须在启动前拒绝
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	// server
+	v.port("server.port", c.Server.Port)
+	v.min("server.pollInterval", c.Server.PollInterval, 100)
+	v.min("server.historySize", c.Server.HistorySize, 1)
+	for i, origin := range c.Server.CORSOrigins {
+		if origin != "*" && !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+			v.add(fmt.Sprintf("server.corsOrigins[%d]", i), `must be "*" or start with http:// or https://, got %q`, origin)
+		}
+	}
+	for i, method := range c.Server.CORSMethods {
+		if !validMethods[method] {
+			v.add(fmt.Sprintf("server.corsMethods[%d]", i), "unknown HTTP method %q", method)
+		}
+	}
+
+	// server.tls
+	tls := c.Server.TLS
+	if tls.Enabled {
+		if tls.CertFile == "" {
+			v.add("server.tls.certFile", "is required when TLS is enabled")
+		}
+		if tls.KeyFile == "" {
+			v.add("server.tls.keyFile", "is required when TLS is enabled")
+		}
+		if tls.RedirectHTTP {
+			v.port("server.tls.httpPort", tls.HTTPPort)
+			if tls.HTTPPort == c.Server.Port {
+				v.add("server.tls.httpPort", "must differ from server.port")
+			}
+		}
+	}
+	switch tls.ClientAuth {
+	case "", "none":
+	case "optional", "require":
+		if tls.ClientCAFile == "" {
+			v.add("server.tls.clientCaFile", "is required when clientAuth is %q", tls.ClientAuth)
+		}
+	default:
+		v.add("server.tls.clientAuth", `must be "none", "optional" or "require", got %q`, tls.ClientAuth)
+	}
+	v.min("server.tls.hstsMaxAge", tls.HSTSMaxAge, 0)
+
+	// storage
+	v.min("storage.scanInterval", c.Storage.ScanInterval, 0)
+	v.min("storage.topN", c.Storage.TopN, 0)
+	v.min("storage.historySize", c.Storage.HistorySize, 0)
+
+	// auth
+	v.min("auth.sessionTtl", c.Auth.SessionTTL, 0)
+	users := make(map[string]bool)
+	for i, u := range c.Auth.Users {
+		path := fmt.Sprintf("auth.users[%d]", i)
+		switch {
+		case u.Username == "":
+			v.add(path+".username", "is required")
+		case users[u.Username]:
+			v.add(path+".username", "duplicate user %q", u.Username)
+		}
+		users[u.Username] = true
+		if u.PasswordHash == "" {
+			v.add(path+".passwordHash", "is required (generate one with the hash-password command)")
+		}
+		if u.Role != "" && !validRoles[strings.ToLower(u.Role)] {
+			v.add(path+".role", `must be "viewer", "operator" or "admin", got %q`, u.Role)
+		}
+	}
+	for i, t := range c.Auth.Tokens {
+		path := fmt.Sprintf("auth.tokens[%d]", i)
+		if !sha256Hex.MatchString(t.TokenHash) {
+			v.add(path+".tokenHash", "must be a hex-encoded SHA-256 hash")
+		}
+		if !users[t.User] {
+			v.add(path+".user", "unknown user %q", t.User)
+		}
+	}
+	for i, p := range c.Auth.Policies {
+		if p.Role != "" && !validRoles[strings.ToLower(p.Role)] {
+			v.add(fmt.Sprintf("auth.policies[%d].role", i), "unknown role %q", p.Role)
+		}
+	}
+	oidc := c.Auth.OIDC
+	if oidc.Enabled {
+		if oidc.Issuer == "" {
+			v.add("auth.oidc.issuer", "is required when OIDC is enabled")
+		}
+		if oidc.ClientID == "" {
+			v.add("auth.oidc.clientId", "is required when OIDC is enabled")
+		}
+		if oidc.RedirectURL == "" {
+			v.add("auth.oidc.redirectUrl", "is required when OIDC is enabled")
+		}
+	}
+	for group, role := range oidc.GroupRoles {
+		if !validRoles[strings.ToLower(role)] {
+			v.add("auth.oidc.groupRoles."+group, "unknown role %q", role)
+		}
+	}
+	if oidc.DefaultRole != "" && !validRoles[strings.ToLower(oidc.DefaultRole)] {
+		v.add("auth.oidc.defaultRole", "unknown role %q", oidc.DefaultRole)
+	}
+
+	// audit
+	if c.Audit.Enabled && c.Audit.Path == "" {
+		v.add("audit.path", "is required when the audit log is enabled")
+	}
+	v.min("audit.maxSizeMb", c.Audit.MaxSizeMB, 0)
+	v.min("audit.maxBackups", c.Audit.MaxBackups, 0)
+
+	// websocket
+	ws := c.WebSocket
+	v.min("websocket.maxConnections", ws.MaxConnections, 0)
+	v.min("websocket.maxConnectionsPerIp", ws.MaxConnectionsPerIP, 0)
+	if ws.MessageRate < 0 {
+		v.add("websocket.messageRate", "must not be negative")
+	}
+	v.min("websocket.messageBurst", ws.MessageBurst, 0)
+	v.min("websocket.minInterval", ws.MinInterval, 100)
+	if ws.MaxInterval < ws.MinInterval {
+		v.add("websocket.maxInterval", "must be at least websocket.minInterval (%d), got %d", ws.MinInterval, ws.MaxInterval)
+	}
+
+	// cluster
+	cl := c.Cluster
+	switch cl.Mode {
+	case "", ModeStandalone:
+	case ModeAgent, ModeHub:
+		if cl.Token == "" {
+			v.add("cluster.token", "is required in %s mode", cl.Mode)
+		}
+		if cl.Mode == ModeAgent && !hubURL.MatchString(cl.HubURL) {
+			v.add("cluster.hubUrl", "must start with http://, https://, ws:// or wss://, got %q", cl.HubURL)
+		}
+	default:
+		v.add("cluster.mode", `must be "standalone", "agent" or "hub", got %q`, cl.Mode)
+	}
+	if cl.NodeID != "" && !nodeID.MatchString(cl.NodeID) {
+		v.add("cluster.nodeId", "must contain only letters, digits, '.', '_' or '-', got %q", cl.NodeID)
+	}
+	v.min("cluster.reportInterval", cl.ReportInterval, 100)
+	v.min("cluster.staleAfter", cl.StaleAfter, 1)
+	v.min("cluster.historySize", cl.HistorySize, 1)
+	v.labels("cluster.labels", cl.Labels)
+	for id, meta := range cl.Nodes {
+		if !nodeID.MatchString(id) {
+			v.add("cluster.nodes."+id, "invalid node id %q", id)
+		}
+		v.labels("cluster.nodes."+id+".labels", meta.Labels)
+	}
+
+	if len(v.errs) > 0 {
+		return &ValidationError{Errors: v.errs}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package config
+
+import (
+	"context"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// watchInterval 检查配置文件是否变化的间隔
+const watchInterval = 2 * time.Second
+
+// restartSettings 修改后需要重启才能生效的配置项
+// 其余配置项（轮询间隔、CORS、WebSocket 限制、网络接口展示、节点标签等）在热加载时立即生效
+var restartSettings = []string{
+	"server.host",
+	"server.port",
+	"server.tls",
+	"websocket.compression",
+	"storage",
+	"audit",
+	"cluster.mode",
+	"cluster.nodeId",
+	"cluster.hubUrl",
+	"cluster.token",
+	"cluster.caFile",
+	"cluster.reportInterval",
+	"cluster.staleAfter",
+	"cluster.historySize",
+	"cluster.includeLocal",
+}
+
+var (
+	listenersMu sync.Mutex
+	listeners   []func(*Config)
+)
+
+// OnChange 注册配置变更的回调，热加载或通过 API 修改配置后调用
+func OnChange(fn func(*Config)) {
+	listenersMu.Lock()
+	defer listenersMu.Unlock()
+	listeners = append(listeners, fn)
+}
+
+// Apply 替换当前配置并通知回调，返回修改了但需要重启才能生效的配置项
+func Apply(cfg *Config) []string {
+	configMu.Lock()
+	old := globalConfig
+	globalConfig = cfg
+	configMu.Unlock()
+
+	listenersMu.Lock()
+	fns := append([]func(*Config){}, listeners...)
+	listenersMu.Unlock()
+	for _, fn := range fns {
+		fn(cfg)
+	}
+
+	if old == nil {
+		return nil
+	}
+	return RestartRequired(old, cfg)
+}
+
+// RestartRequired 比较两份配置，返回修改了但需要重启才能生效的配置项
+func RestartRequired(old, new *Config) []string {
+	oldValues, newValues := settingValues(old), settingValues(new)
+	var changed []string
+	for _, path := range restartSettings {
+		if !reflect.DeepEqual(oldValues[path], newValues[path]) {
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
+
+// settingValues 按 restartSettings 中的路径取出配置值
+func settingValues(cfg *Config) map[string]interface{} {
+	values := make(map[string]interface{}, len(restartSettings))
+	root := reflect.ValueOf(cfg).Elem()
+	for _, path := range restartSettings {
+		v := root
+		for _, name := range strings.Split(path, ".") {
+			v = fieldByJSONName(v, name)
+		}
+		values[path] = v.Interface()
+	}
+	return values
+}
+
+// fieldByJSONName 按 JSON 字段名取结构体字段
+func fieldByJSONName(v reflect.Value, name string) reflect.Value {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ","); tag == name {
+			return v.Field(i)
+		}
+	}
+	panic("config: unknown field " + name)
+}
+
+// Watch 轮询配置文件，内容变化后重新加载并应用，直到 ctx 取消
+// 新配置无效时保留当前配置并记录错误
+func Watch(ctx context.Context) {
+	path := Path()
+	if path == "" {
+		return
+	}
+
+	fileChanged(path)
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if !fileChanged(path) {
+			continue
+		}
+
+		if err := Reload(); err != nil {
+			log.Printf("Config: reload of %s failed, keeping current config: %v", path, err)
+		}
+	}
+}
+
+// Reload 重新读取配置文件并应用
+func Reload() error {
+	updateMu.Lock()
+	defer updateMu.Unlock()
+
+	path := Path()
+	cfg, err := readConfig(path, false)
+	if err != nil {
+		return err
+	}
+
+	restart := Apply(cfg)
+	log.Printf("Config: reloaded %s", path)
+	if len(restart) > 0 {
+		log.Printf("Config: changes to %s require a restart to take effect", strings.Join(restart, ", "))
+	}
+	return nil
+}
+
+// stamp 文件的修改时间和大小，用于判断文件是否变化
+type stamp struct {
+	modTime time.Time
+	size    int64
+}
+
+var (
+	stampMu   sync.Mutex
+	lastStamp stamp
+)
+
+// fileChanged 判断文件的 stamp 与上次记录的是否不同，并记录新的 stamp
+// 通过 API 保存配置后也会调用，避免 Watch 重新加载刚写入的文件
+func fileChanged(path string) bool {
+	s := fileStamp(path)
+	stampMu.Lock()
+	defer stampMu.Unlock()
+	if s == lastStamp {
+		return false
+	}
+	lastStamp = s
+	return true
+}
+
+// fileStamp 读取文件的 stamp，文件不存在时返回零值
+func fileStamp(path string) stamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return stamp{}
+	}
+	return stamp{modTime: info.ModTime(), size: info.Size()}
+}
@@ -65,9 +65,9 @@ func GetContainers() []models.DockerContainer {
 			ports := ""
 			for _, p := range c.Ports {
 				if p.PublicPort > 0 {
-					ports += fmt.Sprintf("%d:%s/%s ", p.PublicPort, p.Type, p.PrivatePort)
+					ports += fmt.Sprintf("%d:%d/%s ", p.PublicPort, p.PrivatePort, p.Type)
 				} else {
-					ports += fmt.Sprintf("%s/%s ", p.Type, p.PrivatePort)
+					ports += fmt.Sprintf("%d/%s ", p.PrivatePort, p.Type)
 				}
 			}
 			if len(ports) > 0 {
@@ -97,6 +97,23 @@ func GetContainers() []models.DockerContainer {
 	return result
 }
 
+// InspectContainer 获取容器名称和标签，用于权限判断
+func InspectContainer(containerID string) (name string, labels map[string]string, err error) {
+	if !available || dockerClient == nil {
+		return "", nil, fmt.Errorf("Docker is not available")
+	}
+
+	info, err := dockerClient.ContainerInspect(context.Background(), containerID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if info.Config != nil {
+		labels = info.Config.Labels
+	}
+	return strings.TrimPrefix(info.Name, "/"), labels, nil
+}
+
 // ContainerAction 执行容器操作
 func ContainerAction(containerID, action string) models.ActionResponse {
 	if !available || dockerClient == nil {
@@ -155,6 +172,19 @@ func ContainerAction(containerID, action string) models.ActionResponse {
 			Message: fmt.Sprintf("Container %s restarted", containerID),
 		}
 
+	case "remove":
+		err := dockerClient.ContainerRemove(context.Background(), containerID, types.ContainerRemoveOptions{Force: true})
+		if err != nil {
+			return models.ActionResponse{
+				Success: false,
+				Message: err.Error(),
+			}
+		}
+		return models.ActionResponse{
+			Success: true,
+			Message: fmt.Sprintf("Container %s removed", containerID),
+		}
+
 	default:
 		return models.ActionResponse{
 			Success: false,
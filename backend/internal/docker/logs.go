@@ -0,0 +1,90 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// maxLogLine 单行日志的最大长度
+const maxLogLine = 64 * 1024
+
+// FollowLogs 持续读取容器日志，每行调用一次 fn，直到 ctx 取消或容器停止
+// tail 为开始时回放的历史行数
+func FollowLogs(ctx context.Context, containerID string, tail int, fn func(stream, line string)) error {
+	if !available || dockerClient == nil {
+		return fmt.Errorf("Docker is not available")
+	}
+
+	info, err := dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return fmt.Errorf("Container %s not found", containerID)
+	}
+
+	reader, err := dockerClient.ContainerLogs(ctx, containerID, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+		Tail:       strconv.Itoa(tail),
+	})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	// 使用 TTY 的容器输出未分流，其余容器需要拆分 stdout/stderr
+	if info.Config != nil && info.Config.Tty {
+		return scanLines(reader, "stdout", fn)
+	}
+
+	// stdout 和 stderr 在不同协程中读取，回调需要串行化
+	var mu sync.Mutex
+	serial := func(stream, line string) {
+		mu.Lock()
+		defer mu.Unlock()
+		fn(stream, line)
+	}
+
+	stdoutR, stdoutW := io.Pipe()
+	stderrR, stderrW := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(stdoutW, stderrW, reader)
+		stdoutW.CloseWithError(err)
+		stderrW.CloseWithError(err)
+	}()
+
+	errc := make(chan error, 2)
+	scan := func(r *io.PipeReader, stream string) {
+		err := scanLines(r, stream, serial)
+		// 一侧出错时关闭读端，使 StdCopy 写入失败并结束另一侧
+		r.CloseWithError(err)
+		errc <- err
+	}
+	go scan(stdoutR, "stdout")
+	go scan(stderrR, "stderr")
+
+	err = <-errc
+	if err2 := <-errc; err == nil {
+		err = err2
+	}
+	return err
+}
+
+// scanLines 按行读取并回调
+func scanLines(r io.Reader, stream string, fn func(stream, line string)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 4096), maxLogLine)
+	for scanner.Scan() {
+		fn(stream, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/audit"
+	"github.com/dat-G/MLServer_Dash/backend/internal/auth"
+	"github.com/dat-G/MLServer_Dash/backend/internal/middleware"
+)
+
+// defaultAuditLimit 审计查询默认返回的记录数
+const defaultAuditLimit = 200
+
+// maxAuditLimit 审计查询最多返回的记录数
+const maxAuditLimit = 5000
+
+// AuditLogHandler 查询审计日志（仅 admin）
+// since 支持 RFC3339 时间、Unix 秒或相对时长（如 24h）
+func AuditLogHandler(c *gin.Context) {
+	if err := auth.RequireRole(middleware.CurrentPrincipal(c), auth.RoleAdmin); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"detail": err.Error()})
+		return
+	}
+
+	if !audit.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"detail": "Audit log is not enabled",
+		})
+		return
+	}
+
+	query := audit.Query{
+		User:   c.Query("user"),
+		Target: c.Query("target"),
+		Limit:  defaultAuditLimit,
+	}
+
+	if since := c.Query("since"); since != "" {
+		t, ok := parseSince(since)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"detail": "since must be an RFC3339 time, unix seconds or a duration such as 24h",
+			})
+			return
+		}
+		query.Since = t
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"detail": "limit must be a positive integer",
+			})
+			return
+		}
+		query.Limit = min(n, maxAuditLimit)
+	}
+
+	entries, err := audit.Search(query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// parseSince 解析 since 参数
+func parseSince(s string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, true
+	}
+	if sec, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(sec, 0), true
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return time.Now().Add(-d), true
+	}
+	return time.Time{}, false
+}
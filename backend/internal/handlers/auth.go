@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/auth"
+	"github.com/dat-G/MLServer_Dash/backend/internal/middleware"
+	"github.com/dat-G/MLServer_Dash/backend/internal/models"
+)
+
+// loginRequest 登录请求
+type loginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// LoginHandler 用户名密码登录，成功后写入会话 cookie
+func LoginHandler(c *gin.Context) {
+	if !auth.Enabled() {
+		c.JSON(http.StatusNotFound, gin.H{
+			"detail": "Authentication is not enabled",
+		})
+		return
+	}
+
+	middleware.AuditTarget(c, "auth.login", "")
+
+	var req loginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"detail": "username and password are required",
+		})
+		return
+	}
+
+	middleware.AuditUser(c, req.Username)
+	manager := auth.ManagerInstance
+	session, err := manager.Login(req.Username, req.Password)
+	if err != nil {
+		middleware.AuditMessage(c, err.Error())
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	setSessionCookie(c, session)
+	c.JSON(http.StatusOK, models.AuthStatus{
+		Enabled:  true,
+		Username: session.Username,
+		Role:     manager.UserRole(session.Username).String(),
+		Method:   auth.MethodSession,
+	})
+}
+
+// LogoutHandler 注销当前会话
+func LogoutHandler(c *gin.Context) {
+	if !auth.Enabled() {
+		c.JSON(http.StatusOK, models.ActionResponse{Success: true, Message: "Authentication is not enabled"})
+		return
+	}
+
+	middleware.AuditTarget(c, "auth.logout", "")
+
+	manager := auth.ManagerInstance
+	var logoutURL string
+	if cookie, err := c.Cookie(manager.CookieName()); err == nil {
+		logoutURL = manager.LogoutURL(cookie)
+	}
+	clearSessionCookie(c)
+
+	c.JSON(http.StatusOK, models.LogoutResponse{Success: true, Message: "Logged out", LogoutURL: logoutURL})
+}
+
+// RefreshHandler 延长当前会话；OIDC 会话同时向 provider 刷新令牌
+func RefreshHandler(c *gin.Context) {
+	if !auth.Enabled() {
+		c.JSON(http.StatusNotFound, gin.H{
+			"detail": "Authentication is not enabled",
+		})
+		return
+	}
+
+	manager := auth.ManagerInstance
+	cookie, err := c.Cookie(manager.CookieName())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"detail": "Only cookie sessions can be refreshed",
+		})
+		return
+	}
+
+	session, err := manager.RefreshSession(c.Request.Context(), cookie)
+	if errors.Is(err, auth.ErrRefreshFailed) {
+		// 会话在原有效期内仍然有效，保留 cookie
+		c.JSON(http.StatusBadGateway, gin.H{
+			"detail": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		clearSessionCookie(c)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	setSessionCookie(c, session)
+	MeHandler(c)
+}
+
+// ProvidersHandler 返回可用的登录方式
+func ProvidersHandler(c *gin.Context) {
+	providers := models.AuthProviders{Enabled: auth.Enabled()}
+	if providers.Enabled {
+		manager := auth.ManagerInstance
+		providers.Local = manager.HasLocalUsers()
+		providers.OIDC = manager.OIDCEnabled()
+		if providers.OIDC {
+			providers.OIDCName = manager.OIDCProviderName()
+		}
+	}
+	c.JSON(http.StatusOK, providers)
+}
+
+// OIDCLoginHandler 跳转到 OIDC provider 的授权页面
+func OIDCLoginHandler(c *gin.Context) {
+	if !auth.Enabled() || !auth.ManagerInstance.OIDCEnabled() {
+		c.JSON(http.StatusNotFound, gin.H{
+			"detail": "OIDC login is not enabled",
+		})
+		return
+	}
+
+	authURL, err := auth.ManagerInstance.OIDCLoginURL(c.Request.Context())
+	if err != nil {
+		log.Printf("OIDC: %v", err)
+		c.JSON(http.StatusBadGateway, gin.H{
+			"detail": "Identity provider is unavailable",
+		})
+		return
+	}
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCCallbackHandler 处理 provider 的授权回调，成功后写入会话 cookie 并返回首页
+// 失败时带 sso_error 参数跳回首页，由前端显示错误
+func OIDCCallbackHandler(c *gin.Context) {
+	if !auth.Enabled() || !auth.ManagerInstance.OIDCEnabled() {
+		c.JSON(http.StatusNotFound, gin.H{
+			"detail": "OIDC login is not enabled",
+		})
+		return
+	}
+
+	middleware.AuditTarget(c, "auth.oidc_login", "")
+
+	if providerErr := c.Query("error"); providerErr != "" {
+		message := providerErr
+		if desc := c.Query("error_description"); desc != "" {
+			message += ": " + desc
+		}
+		middleware.AuditFailure(c, message)
+		redirectLoginError(c, message)
+		return
+	}
+
+	session, err := auth.ManagerInstance.OIDCCallback(c.Request.Context(), c.Query("state"), c.Query("code"))
+	if err != nil {
+		log.Printf("OIDC: login failed: %v", err)
+		middleware.AuditFailure(c, err.Error())
+		message := "Single sign-on failed"
+		if errors.Is(err, auth.ErrNoRole) || errors.Is(err, auth.ErrInvalidState) {
+			message = err.Error()
+		}
+		redirectLoginError(c, message)
+		return
+	}
+
+	middleware.AuditUser(c, session.Username)
+	setSessionCookie(c, session)
+	c.Redirect(http.StatusFound, "/")
+}
+
+// redirectLoginError 跳回首页并附带登录错误信息
+func redirectLoginError(c *gin.Context, message string) {
+	c.Redirect(http.StatusFound, "/?sso_error="+url.QueryEscape(message))
+}
+
+// MeHandler 返回当前登录用户
+func MeHandler(c *gin.Context) {
+	status := models.AuthStatus{Enabled: auth.Enabled()}
+	if p := middleware.CurrentPrincipal(c); p != nil {
+		status.Username = p.Username
+		status.Role = p.Role.String()
+		status.Method = p.Method
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// setSessionCookie 写入会话 cookie
+func setSessionCookie(c *gin.Context, session *auth.Session) {
+	manager := auth.ManagerInstance
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     manager.CookieName(),
+		Value:    session.ID,
+		Path:     "/",
+		Expires:  session.ExpiresAt,
+		HttpOnly: true,
+		Secure:   manager.SecureCookie(),
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// clearSessionCookie 清除会话 cookie
+func clearSessionCookie(c *gin.Context) {
+	manager := auth.ManagerInstance
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     manager.CookieName(),
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   manager.SecureCookie(),
+		SameSite: http.SameSiteStrictMode,
+	})
+}
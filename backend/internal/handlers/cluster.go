@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/cluster"
+)
+
+// ClusterGPUsHandler 列出集群中所有 GPU，用于查找空闲的 GPU
+// 支持 min_free_mem（如 40GiB）、model（型号子串）、node（逗号分隔）、idle、idle_for（如 30m）、include_stale
+// 和 selector（节点标签选择器）
+func ClusterGPUsHandler(c *gin.Context) {
+	var filter cluster.GPUFilter
+
+	selector, ok := labelSelector(c)
+	if !ok {
+		return
+	}
+	filter.Selector = selector
+
+	if s := c.Query("min_free_mem"); s != "" {
+		n, err := parseByteSize(s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"detail": "min_free_mem: " + err.Error()})
+			return
+		}
+		filter.MinFreeMemory = n
+	}
+	filter.Model = c.Query("model")
+	if s := c.Query("node"); s != "" {
+		filter.Nodes = strings.Split(s, ",")
+	}
+	if s := c.Query("idle"); s != "" {
+		idle, err := strconv.ParseBool(s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"detail": "idle must be true or false"})
+			return
+		}
+		filter.Idle = &idle
+	}
+	if s := c.Query("idle_for"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"detail": "idle_for must be a duration such as 30m"})
+			return
+		}
+		filter.IdleFor = d
+	}
+	if s := c.Query("include_stale"); s != "" {
+		include, err := strconv.ParseBool(s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"detail": "include_stale must be true or false"})
+			return
+		}
+		filter.IncludeStale = include
+	}
+
+	c.JSON(http.StatusOK, cluster.GPUs(filter))
+}
+
+// byteUnits 容量单位，不区分大小写，G 与 GiB 相同
+var byteUnits = map[string]uint64{
+	"": 1, "b": 1,
+	"k": 1 << 10, "kb": 1 << 10, "kib": 1 << 10,
+	"m": 1 << 20, "mb": 1 << 20, "mib": 1 << 20,
+	"g": 1 << 30, "gb": 1 << 30, "gib": 1 << 30,
+	"t": 1 << 40, "tb": 1 << 40, "tib": 1 << 40,
+}
+
+// parseByteSize 解析 "40GiB"、"512MiB"、"1.5T" 或字节数
+func parseByteSize(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+	n, err := strconv.ParseFloat(s[:i], 64)
+	unit, ok := byteUnits[strings.ToLower(strings.TrimSpace(s[i:]))]
+	if err != nil || !ok || n < 0 {
+		return 0, fmt.Errorf("invalid size %q, expected a value such as 40GiB", s)
+	}
+	return uint64(n * float64(unit)), nil
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/auth"
+	"github.com/dat-G/MLServer_Dash/backend/internal/config"
+	"github.com/dat-G/MLServer_Dash/backend/internal/middleware"
+)
+
+// maxConfigBody 修改配置请求体的最大字节数
+const maxConfigBody = 1 << 20
+
+// ConfigHandler 获取当前生效的配置（仅 admin），不包含密码哈希、token 哈希和 OIDC client secret
+func ConfigHandler(c *gin.Context) {
+	if err := auth.RequireRole(middleware.CurrentPrincipal(c), auth.RoleAdmin); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"path":   config.Path(),
+		"config": config.Public(config.Get()),
+	})
+}
+
+// UpdateConfigHandler 修改配置（仅已认证的 admin，未启用认证时拒绝）
+// 请求体为 JSON Merge Patch，校验通过后写入配置文件并立即生效
+func UpdateConfigHandler(c *gin.Context) {
+	middleware.AuditTarget(c, auth.ActionConfigEdit, "config:"+config.Path())
+
+	if err := auth.AuthorizeAction(middleware.CurrentPrincipal(c), auth.ActionConfigEdit); err != nil {
+		middleware.AuditMessage(c, err.Error())
+		c.JSON(http.StatusForbidden, gin.H{"detail": err.Error()})
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxConfigBody))
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"detail": "request body too large",
+		})
+		return
+	}
+
+	result, err := config.Update(body)
+	if err != nil {
+		middleware.AuditMessage(c, err.Error())
+		var verr *config.ValidationError
+		switch {
+		case errors.As(err, &verr):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"detail": "invalid config",
+				"errors": verr.Errors,
+			})
+		case errors.Is(err, config.ErrInvalidPatch):
+			c.JSON(http.StatusBadRequest, gin.H{"detail": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"detail": err.Error()})
+		}
+		return
+	}
+
+	middleware.AuditParam(c, "changed", strings.Join(result.Changed, ","))
+	if len(result.RestartRequired) > 0 {
+		middleware.AuditMessage(c, "restart required for "+strings.Join(result.RestartRequired, ", "))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"path":            config.Path(),
+		"config":          config.Public(result.Config),
+		"changed":         result.Changed,
+		"restartRequired": result.RestartRequired,
+	})
+}
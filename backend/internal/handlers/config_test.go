@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/auth"
+	"github.com/dat-G/MLServer_Dash/backend/internal/config"
+	"github.com/dat-G/MLServer_Dash/backend/internal/middleware"
+)
+
+func TestUpdateConfigRequiresAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	if auth.Enabled() {
+		t.Fatal("auth unexpectedly enabled")
+	}
+
+	const original = `{"auth": {"enabled": false}}`
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(original), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	oldPath := config.Path()
+	config.SetPath(path)
+	t.Cleanup(func() { config.SetPath(oldPath) })
+
+	r := gin.New()
+	r.Use(middleware.Auth())
+	r.PUT("/api/config", UpdateConfigHandler)
+
+	// 未启用认证时匿名调用者不能通过修改配置开启认证并指定自己的 OIDC provider
+	patch := `{"auth": {"enabled": true, "oidc": {"enabled": true, "issuer": "https://idp.example", "clientId": "x", "defaultRole": "admin"}}}`
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/api/config", strings.NewReader(patch)))
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("PUT /api/config without auth = %d %s, want 403", w.Code, w.Body)
+	}
+	var body struct {
+		Detail string `json:"detail"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body.Detail, "enable auth") {
+		t.Errorf("detail = %q, want a hint to enable auth", body.Detail)
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Errorf("refused update modified the config file:\n%s", data)
+	}
+}
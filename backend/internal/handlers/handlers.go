@@ -1,13 +1,18 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/dat-G/MLServer_Dash/backend/internal/auth"
+	"github.com/dat-G/MLServer_Dash/backend/internal/cluster"
 	"github.com/dat-G/MLServer_Dash/backend/internal/config"
 	"github.com/dat-G/MLServer_Dash/backend/internal/docker"
+	"github.com/dat-G/MLServer_Dash/backend/internal/middleware"
 	"github.com/dat-G/MLServer_Dash/backend/internal/models"
 	"github.com/dat-G/MLServer_Dash/backend/internal/monitor"
 )
@@ -23,6 +28,12 @@ func RootHandler(c *gin.Context) {
 			"system":        "/api/system",
 			"docker":        "/api/docker",
 			"docker_action": "/api/docker/{container_id}/action",
+			"storage_dirs":  "/api/storage/dirs",
+			"process_kill":  "/api/processes/{pid}/kill",
+			"audit":         "/api/audit",
+			"nodes":         "/api/nodes",
+			"node_system":   "/api/nodes/{id}/system",
+			"cluster_gpus":  "/api/cluster/gpus",
 		},
 	})
 }
@@ -30,9 +41,17 @@ func RootHandler(c *gin.Context) {
 // SystemInfoHandler 系统信息处理器
 func SystemInfoHandler(c *gin.Context) {
 	systemInfo := monitor.GetSystemInfo()
+	cluster.RecordLocal(systemInfo)
+	cluster.Annotate(&systemInfo)
 	c.JSON(http.StatusOK, systemInfo)
 }
 
+// StorageDirsHandler 监控目录占用处理器
+// 返回后台扫描的最新结果，不会触发扫描
+func StorageDirsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, monitor.GetDirUsage())
+}
+
 // DockerListHandler Docker容器列表处理器
 func DockerListHandler(c *gin.Context) {
 	// 即使 Docker 不可用也返回空数组，避免前端报错
@@ -59,7 +78,64 @@ func DockerActionHandler(c *gin.Context) {
 		return
 	}
 
+	middleware.AuditTarget(c, "container."+action, "container:"+containerID)
+
+	// 权限检查：先按角色快速拒绝，再根据容器名称和标签匹配策略
+	principal := middleware.CurrentPrincipal(c)
+	if err := auth.AuthorizeAction(principal, action); err != nil {
+		middleware.AuditMessage(c, err.Error())
+		c.JSON(http.StatusForbidden, gin.H{"detail": err.Error()})
+		return
+	}
+	if principal != nil {
+		name, labels, err := docker.InspectContainer(containerID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"detail": fmt.Sprintf("Container %s not found", containerID),
+			})
+			return
+		}
+		middleware.AuditParam(c, "name", name)
+		target := auth.ContainerTarget{ID: containerID, Name: name, Labels: labels}
+		if err := auth.ManagerInstance.AuthorizeContainer(principal, action, target); err != nil {
+			middleware.AuditMessage(c, err.Error())
+			c.JSON(http.StatusForbidden, gin.H{"detail": err.Error()})
+			return
+		}
+	}
+
 	response := docker.ContainerAction(containerID, action)
+	middleware.AuditMessage(c, response.Message)
+	if !response.Success {
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ProcessKillHandler 结束进程处理器（仅 admin）
+func ProcessKillHandler(c *gin.Context) {
+	signal := c.DefaultQuery("signal", "term")
+	middleware.AuditTarget(c, auth.ActionProcessKill, "process:"+c.Param("pid"))
+	middleware.AuditParam(c, "signal", signal)
+
+	if err := auth.AuthorizeAction(middleware.CurrentPrincipal(c), auth.ActionProcessKill); err != nil {
+		middleware.AuditMessage(c, err.Error())
+		c.JSON(http.StatusForbidden, gin.H{"detail": err.Error()})
+		return
+	}
+
+	pid, err := strconv.ParseInt(c.Param("pid"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"detail": "invalid pid",
+		})
+		return
+	}
+
+	response := monitor.KillProcess(int32(pid), signal)
+	middleware.AuditMessage(c, response.Message)
 	if !response.Success {
 		c.JSON(http.StatusInternalServerError, response)
 		return
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/auth"
+	"github.com/dat-G/MLServer_Dash/backend/internal/cluster"
+	"github.com/dat-G/MLServer_Dash/backend/internal/middleware"
+	"github.com/dat-G/MLServer_Dash/backend/internal/models"
+)
+
+// NodesHandler 集群节点列表，单机部署时只有本机，selector 按标签筛选（如 project=nlp,rack!=r2）
+func NodesHandler(c *gin.Context) {
+	selector, ok := labelSelector(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, cluster.Nodes(selector))
+}
+
+// labelSelector 解析 selector 查询参数，格式错误时返回 400
+func labelSelector(c *gin.Context) (cluster.Selector, bool) {
+	selector, err := cluster.ParseSelector(c.Query("selector"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"detail": "selector: " + err.Error()})
+		return cluster.Selector{}, false
+	}
+	return selector, true
+}
+
+// NodeHandler 单个节点的概览
+func NodeHandler(c *gin.Context) {
+	node, err := cluster.Node(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, node)
+}
+
+// NodeSystemHandler 节点最近一次上报的系统信息，格式与 /api/system 相同
+// stale 节点返回断开前的最后一次采样，可通过 /api/nodes/:id 的 status 和 last_seen 判断
+func NodeSystemHandler(c *gin.Context) {
+	sample, err := cluster.Latest(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, sample.System)
+}
+
+// NodeDockerHandler 节点最近一次上报的容器列表
+func NodeDockerHandler(c *gin.Context) {
+	sample, err := cluster.Latest(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, sample.Containers)
+}
+
+// NodeHistoryHandler 节点的历史采样，limit 指定返回最近的条数
+func NodeHistoryHandler(c *gin.Context) {
+	limit := 0
+	if s := c.Query("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"detail": "limit must be a positive integer",
+			})
+			return
+		}
+		limit = n
+	}
+
+	points, err := cluster.History(c.Param("id"), limit)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, points)
+}
+
+// NodeRemoveHandler 删除节点（仅 admin），用于清理已下线机器留下的 stale 节点
+func NodeRemoveHandler(c *gin.Context) {
+	id := c.Param("id")
+	middleware.AuditTarget(c, "node.remove", "node:"+id)
+
+	if err := auth.RequireRole(middleware.CurrentPrincipal(c), auth.RoleAdmin); err != nil {
+		middleware.AuditMessage(c, err.Error())
+		c.JSON(http.StatusForbidden, gin.H{"detail": err.Error()})
+		return
+	}
+
+	if err := cluster.Remove(id); err != nil {
+		middleware.AuditMessage(c, err.Error())
+		status := http.StatusNotFound
+		if errors.Is(err, cluster.ErrLocalNode) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.ActionResponse{
+		Success: true,
+		Message: "Node " + id + " removed",
+	})
+}
@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/audit"
+	"github.com/dat-G/MLServer_Dash/backend/internal/models"
+)
+
+// auditKey gin 上下文中保存审计信息的键
+const auditKey = "audit.info"
+
+// auditInfo 处理器补充的审计信息
+type auditInfo struct {
+	action  string
+	target  string
+	user    string
+	params  map[string]string
+	message string
+	failed  bool
+}
+
+// Audit 审计中间件，需要放在 Auth 之前，以便记录认证失败的请求
+// 记录 /api 下所有修改类请求（POST/PUT/PATCH/DELETE）；其他请求只有在处理器
+// 调用 AuditTarget 时才记录（如 OIDC 登录回调）
+func Audit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !audit.Enabled() || !strings.HasPrefix(c.Request.URL.Path, "/api/") {
+			c.Next()
+			return
+		}
+
+		c.Next()
+
+		info := getAuditInfo(c, false)
+		if info == nil && !isMutating(c.Request.Method) {
+			return
+		}
+		if info == nil {
+			info = &auditInfo{}
+		}
+
+		entry := models.AuditEntry{
+			Time:     time.Now(),
+			User:     info.user,
+			RemoteIP: c.ClientIP(),
+			Action:   info.action,
+			Target:   info.target,
+			Params:   info.params,
+			Status:   c.Writer.Status(),
+			Message:  info.message,
+		}
+		entry.Success = entry.Status < http.StatusBadRequest && !info.failed
+		if entry.Action == "" {
+			route := c.FullPath()
+			if route == "" {
+				route = c.Request.URL.Path
+			}
+			entry.Action = c.Request.Method + " " + route
+		}
+		if p := CurrentPrincipal(c); p != nil {
+			entry.User = p.Username
+			entry.Method = p.Method
+		}
+		if entry.Message == "" && len(c.Errors) > 0 {
+			entry.Message = c.Errors.Last().Error()
+		}
+
+		audit.Record(entry)
+	}
+}
+
+// AuditTarget 设置审计记录的操作名称和目标，调用后即使是 GET 请求也会被记录
+func AuditTarget(c *gin.Context, action, target string) {
+	info := getAuditInfo(c, true)
+	info.action = action
+	info.target = target
+}
+
+// AuditParam 为审计记录添加参数（不要传入密码等敏感信息）
+func AuditParam(c *gin.Context, key, value string) {
+	info := getAuditInfo(c, true)
+	if info.params == nil {
+		info.params = make(map[string]string)
+	}
+	info.params[key] = value
+}
+
+// AuditUser 设置审计记录的用户，用于尚未认证的请求（如登录）
+func AuditUser(c *gin.Context, username string) {
+	getAuditInfo(c, true).user = username
+}
+
+// AuditMessage 设置审计记录的结果说明
+func AuditMessage(c *gin.Context, message string) {
+	getAuditInfo(c, true).message = message
+}
+
+// AuditFailure 将请求记录为失败，用于以重定向等非错误状态码返回的失败
+func AuditFailure(c *gin.Context, message string) {
+	info := getAuditInfo(c, true)
+	info.message = message
+	info.failed = true
+}
+
+// getAuditInfo 读取审计信息，create 为 true 时不存在则创建
+func getAuditInfo(c *gin.Context, create bool) *auditInfo {
+	if v, ok := c.Get(auditKey); ok {
+		if info, ok := v.(*auditInfo); ok {
+			return info
+		}
+	}
+	if !create {
+		return nil
+	}
+	info := &auditInfo{}
+	c.Set(auditKey, info)
+	return info
+}
+
+// isMutating 是否为修改类请求
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
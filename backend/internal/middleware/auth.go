@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/auth"
+)
+
+// principalKey gin 上下文中保存已认证用户的键
+const principalKey = "auth.principal"
+
+// publicPaths 无需用户认证的 API，/api/agent/ws 由 cluster.token 认证
+var publicPaths = map[string]bool{
+	"/api/health":             true,
+	"/api/auth/login":         true,
+	"/api/auth/logout":        true,
+	"/api/auth/providers":     true,
+	"/api/auth/oidc/login":    true,
+	"/api/auth/oidc/callback": true,
+	"/api/agent/ws":           true,
+}
+
+// Auth 认证中间件
+// 保护 /api 下的 REST 接口、/api/ws 升级请求和 /api/stream 事件流，前端静态文件不受影响
+func Auth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !auth.Enabled() {
+			c.Next()
+			return
+		}
+
+		path := c.Request.URL.Path
+		if !strings.HasPrefix(path, "/api/") || publicPaths[path] {
+			c.Next()
+			return
+		}
+
+		principal, err := auth.ManagerInstance.Authenticate(c.Request)
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer realm="mlserver-dash"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"detail": err.Error(),
+			})
+			return
+		}
+
+		c.Set(principalKey, principal)
+		c.Next()
+	}
+}
+
+// CurrentPrincipal 返回当前请求的已认证用户，未启用认证时返回 nil
+func CurrentPrincipal(c *gin.Context) *auth.Principal {
+	if v, ok := c.Get(principalKey); ok {
+		if p, ok := v.(*auth.Principal); ok {
+			return p
+		}
+	}
+	return nil
+}
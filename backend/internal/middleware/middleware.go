@@ -1,13 +1,34 @@
 package middleware
 
 import (
+	"sync/atomic"
+
 	"github.com/dat-G/MLServer_Dash/backend/internal/config"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
+// corsHandler 当前的 CORS 处理器，配置热加载时整体替换
+var corsHandler atomic.Value
+
 // SetupConfig 中间件配置
 func SetupConfig(cfg *config.Config) gin.HandlersChain {
+	ApplyConfig(cfg)
+
+	chain := gin.HandlersChain{
+		CORS(),
+		Audit(),
+		Auth(),
+	}
+	if cfg.Server.TLS.Enabled && cfg.Server.TLS.HSTS {
+		chain = append(gin.HandlersChain{HSTS(cfg.Server.TLS)}, chain...)
+	}
+
+	return chain
+}
+
+// ApplyConfig 按新的 CORS 来源和方法重建 CORS 处理器，配置热加载时调用
+func ApplyConfig(cfg *config.Config) {
 	// CORS配置
 	corsConfig := cors.Config{
 		AllowOrigins:     cfg.Server.CORSOrigins,
@@ -16,8 +37,12 @@ func SetupConfig(cfg *config.Config) gin.HandlersChain {
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 	}
+	corsHandler.Store(cors.New(corsConfig))
+}
 
-	return gin.HandlersChain{
-		cors.New(corsConfig),
+// CORS 使用当前配置处理跨域请求
+func CORS() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		corsHandler.Load().(gin.HandlerFunc)(c)
 	}
 }
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/config"
+)
+
+// HSTS 通过 HTTPS 访问时发送 Strict-Transport-Security 头
+func HSTS(cfg config.TLSConfig) gin.HandlerFunc {
+	maxAge := cfg.HSTSMaxAge
+	if maxAge <= 0 {
+		maxAge = 31536000
+	}
+	value := fmt.Sprintf("max-age=%d", maxAge)
+
+	return func(c *gin.Context) {
+		if c.Request.TLS != nil {
+			c.Header("Strict-Transport-Security", value)
+		}
+		c.Next()
+	}
+}
+
+// HTTPSRedirect 将 HTTP 请求永久重定向到 HTTPS 端口上的同一地址
+func HTTPSRedirect(httpsPort int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if httpsPort != 443 {
+			host = net.JoinHostPort(host, fmt.Sprint(httpsPort))
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
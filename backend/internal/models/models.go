@@ -14,35 +14,148 @@ type GPUMemory struct {
 
 // GPUInfo GPU信息
 type GPUInfo struct {
-	Name               string     `json:"name"`
-	Memory             GPUMemory  `json:"memory"`
-	Utilization        float64    `json:"utilization"`
-	Temperature        int        `json:"temperature"`
-	PowerUsage         int        `json:"power_usage"`          // 当前功耗 (W)
-	PowerLimit         int        `json:"power_limit"`          // 功率限制 (W)
-	EnforcedPowerLimit int        `json:"enforced_power_limit"` // 强制功率限制 (W)
-	PowerDefaultLimit  int        `json:"power_default_limit"`  // 默认功率限制 (W)
+	Name               string       `json:"name"`
+	Memory             GPUMemory    `json:"memory"`
+	Utilization        float64      `json:"utilization"`
+	Temperature        int          `json:"temperature"`
+	PowerUsage         int          `json:"power_usage"`          // 当前功耗 (W)
+	PowerLimit         int          `json:"power_limit"`          // 功率限制 (W)
+	EnforcedPowerLimit int          `json:"enforced_power_limit"` // 强制功率限制 (W)
+	PowerDefaultLimit  int          `json:"power_default_limit"`  // 默认功率限制 (W)
+	Index              int          `json:"index"`                // nvidia-smi 中的序号
+	UUID               string       `json:"uuid,omitempty"`
+	Processes          []GPUProcess `json:"processes,omitempty"` // 使用该 GPU 的计算进程
+}
+
+// GPUProcess 使用 GPU 的计算进程
+type GPUProcess struct {
+	PID         int32  `json:"pid"`
+	Name        string `json:"name"`
+	User        string `json:"user,omitempty"`
+	UsedMemory  uint64 `json:"used_memory"`            // 占用的显存（字节）
+	ContainerID string `json:"container_id,omitempty"` // 所在容器的短 ID
+	Container   string `json:"container,omitempty"`    // 所在容器的名称
 }
 
 // CPUInfo CPU信息
 type CPUInfo struct {
-	Brand           string    `json:"brand"`
-	Percent         float64   `json:"percent"`
-	Cores           int       `json:"cores"`
-	Threads         int       `json:"threads"`
-	PerCorePercent  []float64 `json:"per_core_percent"`
+	Brand          string           `json:"brand"`
+	Percent        float64          `json:"percent"`
+	Cores          int              `json:"cores"`
+	Threads        int              `json:"threads"`
+	PerCorePercent []float64        `json:"per_core_percent"`
+	Sockets        int              `json:"sockets"`
+	NUMANodes      int              `json:"numa_nodes"`
+	Flags          []string         `json:"flags,omitempty"` // ML 相关指令集 (avx512/amx 等)
+	Times          *CPUTimesPercent `json:"times,omitempty"` // 总体时间占比
+	PerCore        []CoreInfo       `json:"per_core,omitempty"`
+}
+
+// CPUTimesPercent CPU 时间占比（百分比）
+type CPUTimesPercent struct {
+	User    float64 `json:"user"`
+	Nice    float64 `json:"nice"`
+	System  float64 `json:"system"`
+	Idle    float64 `json:"idle"`
+	IOWait  float64 `json:"iowait"`
+	IRQ     float64 `json:"irq"`
+	SoftIRQ float64 `json:"softirq"`
+	Steal   float64 `json:"steal"`
+}
+
+// CoreInfo 单个逻辑核心信息
+type CoreInfo struct {
+	ID       int             `json:"id"`
+	Percent  float64         `json:"percent"`
+	Times    CPUTimesPercent `json:"times"`
+	Freq     float64         `json:"freq,omitempty"`     // 当前频率 (MHz)
+	MinFreq  float64         `json:"min_freq,omitempty"` // 最低频率 (MHz)
+	MaxFreq  float64         `json:"max_freq,omitempty"` // 最高频率 (MHz)
+	Socket   int             `json:"socket"`
+	Core     int             `json:"core"`
+	NUMANode int             `json:"numa_node"`          // -1 表示未知
+	Siblings []int           `json:"siblings,omitempty"` // SMT 兄弟线程
 }
 
 // MemoryInfo 内存信息
 type MemoryInfo struct {
+	Total          uint64           `json:"total"`
+	Used           uint64           `json:"used"`
+	Free           uint64           `json:"free"`
+	Percent        float64          `json:"percent"`
+	TotalHuman     string           `json:"total_human"`
+	UsedHuman      string           `json:"used_human"`
+	FreeHuman      string           `json:"free_human"`
+	Available      uint64           `json:"available"`
+	AvailableHuman string           `json:"available_human"`
+	Cached         uint64           `json:"cached"`     // 页缓存
+	Buffers        uint64           `json:"buffers"`    // 块设备缓冲
+	Shared         uint64           `json:"shared"`     // 共享内存（含 tmpfs/shm）
+	Slab           uint64           `json:"slab"`       // 内核 slab
+	Dirty          uint64           `json:"dirty"`      // 等待写回的脏页
+	WriteBack      uint64           `json:"write_back"` // 正在写回的页
+	Model          string           `json:"model,omitempty"`
+	Swap           *SwapInfo        `json:"swap,omitempty"`
+	Shm            *ShmInfo         `json:"shm,omitempty"`
+	HugePages      []HugePagePool   `json:"huge_pages,omitempty"`
+	NUMA           []NUMANodeMemory `json:"numa,omitempty"`
+	Modules        []MemoryModule   `json:"modules,omitempty"`
+}
+
+// SwapInfo 交换分区信息
+type SwapInfo struct {
+	Total      uint64   `json:"total"`
+	Used       uint64   `json:"used"`
+	Free       uint64   `json:"free"`
+	Percent    float64  `json:"percent"`
+	TotalHuman string   `json:"total_human"`
+	UsedHuman  string   `json:"used_human"`
+	SwapIn     *float64 `json:"swap_in,omitempty"`  // 换入速率 (bytes/s)
+	SwapOut    *float64 `json:"swap_out,omitempty"` // 换出速率 (bytes/s)
+}
+
+// ShmInfo /dev/shm 使用情况
+type ShmInfo struct {
+	Total      uint64  `json:"total"`
+	Used       uint64  `json:"used"`
+	Percent    float64 `json:"percent"`
+	TotalHuman string  `json:"total_human"`
+	UsedHuman  string  `json:"used_human"`
+}
+
+// HugePagePool 大页内存池
+type HugePagePool struct {
+	PageSize      uint64 `json:"page_size"`
+	PageSizeHuman string `json:"page_size_human"`
+	Total         uint64 `json:"total"`    // 页数
+	Free          uint64 `json:"free"`     // 页数
+	Reserved      uint64 `json:"reserved"` // 页数
+	Surplus       uint64 `json:"surplus"`  // 页数
+}
+
+// NUMANodeMemory NUMA 节点内存使用
+type NUMANodeMemory struct {
+	Node       int     `json:"node"`
 	Total      uint64  `json:"total"`
 	Used       uint64  `json:"used"`
 	Free       uint64  `json:"free"`
+	FilePages  uint64  `json:"file_pages"`
 	Percent    float64 `json:"percent"`
 	TotalHuman string  `json:"total_human"`
 	UsedHuman  string  `json:"used_human"`
-	FreeHuman  string  `json:"free_human"`
-	Model      string  `json:"model,omitempty"`
+}
+
+// MemoryModule 内存条信息（来自 dmidecode）
+type MemoryModule struct {
+	Locator         string `json:"locator,omitempty"`
+	Size            uint64 `json:"size"`
+	SizeHuman       string `json:"size_human"`
+	Type            string `json:"type,omitempty"`
+	Speed           int    `json:"speed,omitempty"`            // 标称速率 (MT/s)
+	ConfiguredSpeed int    `json:"configured_speed,omitempty"` // 实际运行速率 (MT/s)
+	Manufacturer    string `json:"manufacturer,omitempty"`
+	PartNumber      string `json:"part_number,omitempty"`
+	ECC             bool   `json:"ecc"` // 总位宽大于数据位宽（如 72/64 bits）
 }
 
 // DiskInfo 磁盘信息
@@ -59,21 +172,85 @@ type DiskInfo struct {
 
 // NetworkInterface 网络接口信息
 type NetworkInterface struct {
-	Name      string  `json:"name"`
-	Model     string  `json:"model,omitempty"`
-	Speed     *int    `json:"speed,omitempty"`
-	IPv4      string  `json:"ipv4,omitempty"`
-	IPv6      string  `json:"ipv6,omitempty"`
-	BytesSent uint64  `json:"bytes_sent"`
-	BytesRecv uint64  `json:"bytes_recv"`
-	PacketsSent uint64 `json:"packets_sent"`
-	PacketsRecv uint64 `json:"packets_recv"`
-	ErrorsIn  int     `json:"errors_in"`
-	ErrorsOut int     `json:"errors_out"`
-	DropsIn   int     `json:"drops_in"`
-	DropsOut  int     `json:"drops_out"`
-	SpeedUp   *float64 `json:"speed_up,omitempty"`
-	SpeedDown *float64 `json:"speed_down,omitempty"`
+	Name        string   `json:"name"`
+	Model       string   `json:"model,omitempty"`
+	Driver      string   `json:"driver,omitempty"`
+	Speed       *int     `json:"speed,omitempty"`  // 链路速率 (Mb/s)
+	Duplex      string   `json:"duplex,omitempty"` // full / half
+	MTU         int      `json:"mtu,omitempty"`
+	OperState   string   `json:"operstate,omitempty"` // up / down / unknown ...
+	Virtual     bool     `json:"virtual"`
+	Bridge      bool     `json:"bridge,omitempty"`
+	Master      string   `json:"master,omitempty"`  // 所属网桥/bond
+	Members     []string `json:"members,omitempty"` // 归入该网桥的虚拟接口（groupVirtual）
+	IPv4        string   `json:"ipv4,omitempty"`
+	IPv6        string   `json:"ipv6,omitempty"`
+	IPv4Addrs   []string `json:"ipv4_addrs,omitempty"`
+	IPv6Addrs   []string `json:"ipv6_addrs,omitempty"`
+	BytesSent   uint64   `json:"bytes_sent"`
+	BytesRecv   uint64   `json:"bytes_recv"`
+	PacketsSent uint64   `json:"packets_sent"`
+	PacketsRecv uint64   `json:"packets_recv"`
+	ErrorsIn    int      `json:"errors_in"`
+	ErrorsOut   int      `json:"errors_out"`
+	DropsIn     int      `json:"drops_in"`
+	DropsOut    int      `json:"drops_out"`
+	SpeedUp     *float64 `json:"speed_up,omitempty"`
+	SpeedDown   *float64 `json:"speed_down,omitempty"`
+}
+
+// RDMAPort InfiniBand/RoCE 端口信息
+type RDMAPort struct {
+	Device       string   `json:"device"`
+	Port         int      `json:"port"`
+	State        string   `json:"state"`      // ACTIVE / DOWN ...
+	PhysState    string   `json:"phys_state"` // LinkUp / Disabled ...
+	Rate         string   `json:"rate"`
+	RateGbps     float64  `json:"rate_gbps"`
+	LinkLayer    string   `json:"link_layer"` // InfiniBand / Ethernet
+	NetDev       string   `json:"netdev,omitempty"`
+	XmitBytes    uint64   `json:"xmit_bytes"`
+	RcvBytes     uint64   `json:"rcv_bytes"`
+	XmitPackets  uint64   `json:"xmit_packets"`
+	RcvPackets   uint64   `json:"rcv_packets"`
+	RcvErrors    uint64   `json:"rcv_errors"`
+	XmitDiscards uint64   `json:"xmit_discards"`
+	SymbolErrors uint64   `json:"symbol_errors"`
+	LinkDowned   uint64   `json:"link_downed"`
+	SpeedUp      *float64 `json:"speed_up,omitempty"`
+	SpeedDown    *float64 `json:"speed_down,omitempty"`
+}
+
+// LoadInfo 系统负载信息
+type LoadInfo struct {
+	Load1           float64  `json:"load1"`
+	Load5           float64  `json:"load5"`
+	Load15          float64  `json:"load15"`
+	ProcsRunning    int      `json:"procs_running"`              // 运行队列长度
+	ProcsBlocked    int      `json:"procs_blocked"`              // 等待 IO 的进程数
+	ContextSwitches *float64 `json:"context_switches,omitempty"` // 每秒上下文切换次数
+	Interrupts      *float64 `json:"interrupts,omitempty"`       // 每秒中断次数
+}
+
+// PressureStat PSI 单行统计（百分比）
+type PressureStat struct {
+	Avg10  float64 `json:"avg10"`
+	Avg60  float64 `json:"avg60"`
+	Avg300 float64 `json:"avg300"`
+	Total  uint64  `json:"total"` // 累计停顿时间 (us)
+}
+
+// PressureResource 单个资源的 PSI 信息
+type PressureResource struct {
+	Some *PressureStat `json:"some,omitempty"`
+	Full *PressureStat `json:"full,omitempty"`
+}
+
+// PressureInfo Linux Pressure Stall Information
+type PressureInfo struct {
+	CPU    *PressureResource `json:"cpu,omitempty"`
+	Memory *PressureResource `json:"memory,omitempty"`
+	IO     *PressureResource `json:"io,omitempty"`
 }
 
 // DistroInfo 发行版信息
@@ -84,16 +261,53 @@ type DistroInfo struct {
 
 // SystemInfo 系统信息
 type SystemInfo struct {
-	Hostname   string             `json:"hostname"`
-	OS         string             `json:"os"`
-	Distro     *DistroInfo        `json:"distro,omitempty"`
-	CPU        CPUInfo            `json:"cpu"`
-	Memory     MemoryInfo         `json:"memory"`
-	Disks      []DiskInfo         `json:"disks"`
-	Uptime     int                `json:"uptime"`
-	GPU        []GPUInfo          `json:"gpu,omitempty"`
-	Network    []NetworkInterface `json:"network,omitempty"`
-	WSClients  int                `json:"ws_clients,omitempty"` // WebSocket 连接数
+	Hostname  string             `json:"hostname"`
+	OS        string             `json:"os"`
+	Distro    *DistroInfo        `json:"distro,omitempty"`
+	CPU       CPUInfo            `json:"cpu"`
+	Memory    MemoryInfo         `json:"memory"`
+	Disks     []DiskInfo         `json:"disks"`
+	Uptime    int                `json:"uptime"`
+	Load      *LoadInfo          `json:"load,omitempty"`
+	Pressure  *PressureInfo      `json:"pressure,omitempty"`
+	GPU       []GPUInfo          `json:"gpu,omitempty"`
+	Network   []NetworkInterface `json:"network,omitempty"`
+	RDMA      []RDMAPort         `json:"rdma,omitempty"`
+	WSClients int                `json:"ws_clients,omitempty"` // WebSocket 连接数
+	NodeID    string             `json:"node_id,omitempty"`
+	Labels    map[string]string  `json:"labels,omitempty"` // 节点标签（project、rack、owner 等）
+	Notes     string             `json:"notes,omitempty"`  // 节点备注
+}
+
+// DirEntryUsage 目录下单个子项的占用
+type DirEntryUsage struct {
+	Name      string `json:"name"`
+	Size      uint64 `json:"size"`
+	SizeHuman string `json:"size_human"`
+	Files     uint64 `json:"files"`
+	IsDir     bool   `json:"is_dir"`
+}
+
+// DirUsageSample 目录占用历史记录
+type DirUsageSample struct {
+	Timestamp time.Time `json:"timestamp"`
+	Size      uint64    `json:"size"`
+	Files     uint64    `json:"files"`
+}
+
+// DirUsage 监控目录的占用统计
+type DirUsage struct {
+	Path         string           `json:"path"`
+	Size         uint64           `json:"size"`
+	SizeHuman    string           `json:"size_human"`
+	Files        uint64           `json:"files"`
+	Dirs         uint64           `json:"dirs"`
+	Scanning     bool             `json:"scanning"`
+	LastScan     *time.Time       `json:"last_scan,omitempty"`
+	ScanDuration float64          `json:"scan_duration"` // 上次扫描耗时（秒）
+	Error        string           `json:"error,omitempty"`
+	Top          []DirEntryUsage  `json:"top"`
+	History      []DirUsageSample `json:"history"`
 }
 
 // DockerContainer Docker容器信息
@@ -112,6 +326,121 @@ type ActionResponse struct {
 	Message string `json:"message"`
 }
 
+// AuthStatus 当前认证状态
+type AuthStatus struct {
+	Enabled  bool   `json:"enabled"`
+	Username string `json:"username,omitempty"`
+	Role     string `json:"role,omitempty"`   // viewer / operator / admin
+	Method   string `json:"method,omitempty"` // session / token / oidc
+}
+
+// AuthProviders 可用的登录方式，供前端渲染登录页
+type AuthProviders struct {
+	Enabled  bool   `json:"enabled"`
+	Local    bool   `json:"local"`               // 是否配置了本地用户
+	OIDC     bool   `json:"oidc"`                // 是否启用 OIDC 单点登录
+	OIDCName string `json:"oidc_name,omitempty"` // 登录按钮显示的名称
+}
+
+// LogoutResponse 注销响应，OIDC 会话附带 provider 的注销地址
+type LogoutResponse struct {
+	Success   bool   `json:"success"`
+	Message   string `json:"message"`
+	LogoutURL string `json:"logout_url,omitempty"`
+}
+
+// ProcessInfo 进程信息
+type ProcessInfo struct {
+	PID           int32   `json:"pid"`
+	Name          string  `json:"name"`
+	Username      string  `json:"username,omitempty"`
+	Status        string  `json:"status,omitempty"`
+	CPUPercent    float64 `json:"cpu_percent"` // 两次采集之间的占用，多核可超过 100
+	MemoryRSS     uint64  `json:"memory_rss"`
+	MemoryPercent float32 `json:"memory_percent"`
+	Threads       int32   `json:"threads"`
+	Cmdline       string  `json:"cmdline,omitempty"`
+	CreateTime    int64   `json:"create_time"` // Unix 毫秒
+}
+
+// ContainerLogLine 容器日志行
+type ContainerLogLine struct {
+	Container string    `json:"container"`
+	Stream    string    `json:"stream"` // stdout / stderr
+	Line      string    `json:"line"`
+	Time      time.Time `json:"time"`
+}
+
+// AuditEntry 审计记录
+type AuditEntry struct {
+	Time     time.Time         `json:"time"`
+	User     string            `json:"user,omitempty"`   // 未启用认证时为空
+	Method   string            `json:"method,omitempty"` // 认证方式
+	RemoteIP string            `json:"remote_ip"`
+	Action   string            `json:"action"`           // 如 container.restart、process.kill
+	Target   string            `json:"target,omitempty"` // 如 container:<name>、process:<pid>
+	Params   map[string]string `json:"params,omitempty"`
+	Status   int               `json:"status"` // HTTP 状态码
+	Success  bool              `json:"success"`
+	Message  string            `json:"message,omitempty"`
+}
+
+// NodeSample 节点的一次采样，agent 上报或 hub 本机采集
+type NodeSample struct {
+	Timestamp  time.Time         `json:"timestamp"`
+	System     SystemInfo        `json:"system"`
+	Containers []DockerContainer `json:"containers"`
+}
+
+// NodeSummary 集群节点概览
+type NodeSummary struct {
+	ID             string            `json:"id"`
+	Hostname       string            `json:"hostname"`
+	Status         string            `json:"status"` // online / stale
+	Local          bool              `json:"local"`  // 当前服务所在的机器
+	Address        string            `json:"address,omitempty"`
+	ConnectedAt    *time.Time        `json:"connected_at,omitempty"` // 当前连接建立的时间，未连接时为空
+	LastSeen       time.Time         `json:"last_seen"`
+	Uptime         int               `json:"uptime"`
+	CPUPercent     float64           `json:"cpu_percent"`
+	MemoryPercent  float64           `json:"memory_percent"`
+	GPUCount       int               `json:"gpu_count"`
+	GPUUtilization float64           `json:"gpu_utilization"` // 所有 GPU 的平均利用率
+	Containers     int               `json:"containers"`      // 运行中的容器数
+	Labels         map[string]string `json:"labels"`
+	Notes          string            `json:"notes,omitempty"`
+}
+
+// NodeHistoryPoint 节点历史采样中的主要指标
+type NodeHistoryPoint struct {
+	Timestamp        time.Time `json:"timestamp"`
+	CPUPercent       float64   `json:"cpu_percent"`
+	MemoryPercent    float64   `json:"memory_percent"`
+	GPUUtilization   []float64 `json:"gpu_utilization,omitempty"`    // 按 GPU 序号
+	GPUMemoryPercent []float64 `json:"gpu_memory_percent,omitempty"` // 按 GPU 序号
+}
+
+// ClusterGPU 集群中的一块 GPU
+type ClusterGPU struct {
+	Node            string            `json:"node"`
+	NodeStatus      string            `json:"node_status"` // online / stale
+	NodeLabels      map[string]string `json:"node_labels"`
+	Index           int               `json:"index"`
+	UUID            string            `json:"uuid,omitempty"`
+	Model           string            `json:"model"`
+	MemoryTotal     uint64            `json:"memory_total"`
+	MemoryUsed      uint64            `json:"memory_used"`
+	MemoryFree      uint64            `json:"memory_free"`
+	MemoryFreeHuman string            `json:"memory_free_human"`
+	Utilization     float64           `json:"utilization"`
+	Temperature     int               `json:"temperature"`
+	Idle            bool              `json:"idle"`                 // 没有计算进程且利用率低于阈值
+	IdleSince       *time.Time        `json:"idle_since,omitempty"` // 连续空闲的起始时间（不早于开始观察的时间）
+	Processes       []GPUProcess      `json:"processes"`
+	Containers      []string          `json:"containers"` // 使用该 GPU 的容器名称
+	SampledAt       time.Time         `json:"sampled_at"`
+}
+
 // HealthResponse 健康检查响应
 type HealthResponse struct {
 	Status          string    `json:"status"`
@@ -122,7 +451,7 @@ type HealthResponse struct {
 
 // RootResponse 根端点响应
 type RootResponse struct {
-	Message  string            `json:"message"`
-	Version  string            `json:"version"`
+	Message   string            `json:"message"`
+	Version   string            `json:"version"`
 	Endpoints map[string]string `json:"endpoints"`
 }
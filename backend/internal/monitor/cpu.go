@@ -0,0 +1,390 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/models"
+)
+
+// cpuSampleInterval CPU 采样间隔
+// 占用率在采集器中按固定间隔计算，REST 和 WebSocket 读取同一份结果，互不干扰
+const cpuSampleInterval = time.Second
+
+// mlCPUFlags 与机器学习推理/训练相关的 CPU 指令集
+var mlCPUFlags = []string{
+	"sse4_2", "avx", "avx2", "fma", "f16c",
+	"avx_vnni", "avx512f", "avx512bw", "avx512vl", "avx512_vnni",
+	"avx512_bf16", "avx512_fp16", "amx_tile", "amx_bf16", "amx_int8",
+}
+
+// cpuStatic 不会变化的 CPU 信息，只读取一次
+type cpuStatic struct {
+	brand     string
+	cores     int
+	threads   int
+	sockets   int
+	numaNodes int
+	flags     []string
+	topology  map[int]models.CoreInfo // 键为 CPU 编号（cpuN 中的 N），仅包含拓扑和频率范围
+}
+
+// cpuCollector 按固定间隔采样 CPU 时间
+type cpuCollector struct {
+	mu      sync.RWMutex
+	last    []cpu.TimesStat
+	perCore []models.CPUTimesPercent
+	ids     []int // perCore 对应的 CPU 编号，CPU 离线时编号不连续
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var (
+	cpuStaticOnce sync.Once
+	cpuStaticInfo cpuStatic
+	cpuSampler    *cpuCollector
+)
+
+// InitCPUCollector 启动 CPU 采集器，parent 取消或调用 StopCPUCollector 后停止
+func InitCPUCollector(parent context.Context) {
+	c := &cpuCollector{done: make(chan struct{})}
+	c.last, _ = cpu.Times(true)
+
+	ctx, cancel := context.WithCancel(parent)
+	c.cancel = cancel
+	cpuSampler = c
+
+	go c.run(ctx)
+}
+
+// StopCPUCollector 停止 CPU 采集器
+func StopCPUCollector() {
+	if cpuSampler == nil {
+		return
+	}
+	cpuSampler.cancel()
+	<-cpuSampler.done
+}
+
+// run 采样主循环
+func (c *cpuCollector) run(ctx context.Context) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(cpuSampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.sample()
+		}
+	}
+}
+
+// sample 读取一次 CPU 时间并计算与上次采样之间的占比
+func (c *cpuCollector) sample() {
+	times, err := cpu.Times(true)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// CPU 上线或离线后与上次采样无法逐个对应，等下一次采样
+	if sameCPUs(c.last, times) {
+		perCore := make([]models.CPUTimesPercent, len(times))
+		for i := range times {
+			perCore[i] = cpuTimesDelta(c.last[i], times[i])
+		}
+		c.perCore = perCore
+		c.ids = cpuIDs(times)
+	}
+	c.last = times
+}
+
+// snapshot 返回最近一次的每核心占比及对应的 CPU 编号
+func (c *cpuCollector) snapshot() ([]int, []models.CPUTimesPercent) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]int(nil), c.ids...), append([]models.CPUTimesPercent(nil), c.perCore...)
+}
+
+// sameCPUs 两次采样是否包含相同的 CPU
+func sameCPUs(a, b []cpu.TimesStat) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].CPU != b[i].CPU {
+			return false
+		}
+	}
+	return true
+}
+
+// cpuIDs 从 cpu.Times 的 CPU 字段（"cpuN"）解析 CPU 编号，无法解析时使用下标
+func cpuIDs(times []cpu.TimesStat) []int {
+	ids := make([]int, len(times))
+	for i, t := range times {
+		ids[i] = i
+		if n, ok := strings.CutPrefix(t.CPU, "cpu"); ok {
+			if id, err := strconv.Atoi(n); err == nil {
+				ids[i] = id
+			}
+		}
+	}
+	return ids
+}
+
+// cpuTimesDelta 计算两次采样之间各类时间的百分比
+func cpuTimesDelta(t1, t2 cpu.TimesStat) models.CPUTimesPercent {
+	total := cpuTotal(t2) - cpuTotal(t1)
+	if total <= 0 {
+		return models.CPUTimesPercent{Idle: 100}
+	}
+	pct := func(a, b float64) float64 {
+		v := (b - a) / total * 100
+		if v < 0 {
+			return 0
+		}
+		if v > 100 {
+			return 100
+		}
+		return v
+	}
+	return models.CPUTimesPercent{
+		User:    pct(t1.User, t2.User),
+		Nice:    pct(t1.Nice, t2.Nice),
+		System:  pct(t1.System, t2.System),
+		Idle:    pct(t1.Idle, t2.Idle),
+		IOWait:  pct(t1.Iowait, t2.Iowait),
+		IRQ:     pct(t1.Irq, t2.Irq),
+		SoftIRQ: pct(t1.Softirq, t2.Softirq),
+		Steal:   pct(t1.Steal, t2.Steal),
+	}
+}
+
+// cpuTotal 总时间，Linux 上 guest 时间已计入 user/nice
+func cpuTotal(t cpu.TimesStat) float64 {
+	total := t.Total()
+	if runtime.GOOS == "linux" {
+		total -= t.Guest + t.GuestNice
+	}
+	return total
+}
+
+// busyPercent 与 gopsutil 一致：非 idle 且非 iowait 的时间
+func busyPercent(t models.CPUTimesPercent) float64 {
+	busy := 100 - t.Idle - t.IOWait
+	if busy < 0 {
+		return 0
+	}
+	return busy
+}
+
+// GetCPUInfo 获取CPU信息
+func GetCPUInfo() models.CPUInfo {
+	static := getCPUStatic()
+
+	var ids []int
+	var perCoreTimes []models.CPUTimesPercent
+	if cpuSampler != nil {
+		ids, perCoreTimes = cpuSampler.snapshot()
+	}
+
+	perCorePercent := make([]float64, len(perCoreTimes))
+	perCore := make([]models.CoreInfo, len(perCoreTimes))
+	var total models.CPUTimesPercent
+	for i, t := range perCoreTimes {
+		perCorePercent[i] = busyPercent(t)
+
+		if core, ok := static.topology[ids[i]]; ok {
+			perCore[i] = core
+		} else {
+			perCore[i] = models.CoreInfo{ID: ids[i], NUMANode: -1}
+		}
+		perCore[i].Percent = perCorePercent[i]
+		perCore[i].Times = t
+		perCore[i].Freq = readCPUFreq(perCore[i].ID)
+
+		total.User += t.User
+		total.Nice += t.Nice
+		total.System += t.System
+		total.Idle += t.Idle
+		total.IOWait += t.IOWait
+		total.IRQ += t.IRQ
+		total.SoftIRQ += t.SoftIRQ
+		total.Steal += t.Steal
+	}
+
+	// 计算总体占用率
+	var totalPercent float64
+	var times *models.CPUTimesPercent
+	if n := float64(len(perCoreTimes)); n > 0 {
+		total.User /= n
+		total.Nice /= n
+		total.System /= n
+		total.Idle /= n
+		total.IOWait /= n
+		total.IRQ /= n
+		total.SoftIRQ /= n
+		total.Steal /= n
+		times = &total
+		totalPercent = busyPercent(total)
+	}
+
+	return models.CPUInfo{
+		Brand:          static.brand,
+		Percent:        totalPercent,
+		Cores:          static.cores,
+		Threads:        static.threads,
+		PerCorePercent: perCorePercent,
+		Sockets:        static.sockets,
+		NUMANodes:      static.numaNodes,
+		Flags:          static.flags,
+		Times:          times,
+		PerCore:        perCore,
+	}
+}
+
+// getCPUStatic 读取型号、核心数、指令集和拓扑
+func getCPUStatic() cpuStatic {
+	cpuStaticOnce.Do(func() {
+		info, _ := cpu.Info()
+
+		var s cpuStatic
+		if len(info) > 0 {
+			s.brand = info[0].ModelName
+			s.flags = filterMLFlags(info[0].Flags)
+		}
+		if s.brand == "" {
+			s.brand = runtime.GOARCH + " CPU"
+		}
+
+		// 获取物理核心数和逻辑核心数
+		s.cores, _ = cpu.Counts(false)
+		s.threads, _ = cpu.Counts(true)
+
+		// 按 cpu.Times 中的 CPU 编号读取拓扑，存在离线 CPU 时编号与下标不一致
+		ids := make([]int, s.threads)
+		for i := range ids {
+			ids[i] = i
+		}
+		if times, err := cpu.Times(true); err == nil && len(times) > 0 {
+			ids = cpuIDs(times)
+		}
+		s.topology = readCPUTopology(ids)
+		sockets := make(map[int]bool)
+		nodes := make(map[int]bool)
+		for _, core := range s.topology {
+			sockets[core.Socket] = true
+			if core.NUMANode >= 0 {
+				nodes[core.NUMANode] = true
+			}
+		}
+		s.sockets = len(sockets)
+		s.numaNodes = len(nodes)
+
+		cpuStaticInfo = s
+	})
+	return cpuStaticInfo
+}
+
+// filterMLFlags 筛选 ML 相关的指令集
+func filterMLFlags(flags []string) []string {
+	present := make(map[string]bool, len(flags))
+	for _, f := range flags {
+		present[f] = true
+	}
+
+	result := []string{}
+	for _, f := range mlCPUFlags {
+		if present[f] {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// readCPUTopology 从 /sys/devices/system/cpu/cpuN 读取给定 CPU 的拓扑和频率范围，键为 CPU 编号
+func readCPUTopology(ids []int) map[int]models.CoreInfo {
+	topology := make(map[int]models.CoreInfo, len(ids))
+	for _, id := range ids {
+		dir := filepath.Join(sysRoot, "devices", "system", "cpu", fmt.Sprintf("cpu%d", id))
+
+		core := models.CoreInfo{
+			ID:       id,
+			Socket:   int(readUintFile(filepath.Join(dir, "topology", "physical_package_id"))),
+			Core:     int(readUintFile(filepath.Join(dir, "topology", "core_id"))),
+			NUMANode: -1,
+			Siblings: parseCPUList(readStringFile(filepath.Join(dir, "topology", "thread_siblings_list"))),
+			MinFreq:  float64(readUintFile(filepath.Join(dir, "cpufreq", "cpuinfo_min_freq"))) / 1000,
+			MaxFreq:  float64(readUintFile(filepath.Join(dir, "cpufreq", "cpuinfo_max_freq"))) / 1000,
+		}
+
+		// cpuN 目录下的 nodeX 链接表示所属 NUMA 节点
+		if matches, _ := filepath.Glob(filepath.Join(dir, "node[0-9]*")); len(matches) > 0 {
+			if node, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(matches[0]), "node")); err == nil {
+				core.NUMANode = node
+			}
+		}
+
+		topology[id] = core
+	}
+	return topology
+}
+
+// readCPUFreq 读取当前频率 (MHz)，不支持 cpufreq 时返回 0
+func readCPUFreq(id int) float64 {
+	path := filepath.Join(sysRoot, "devices", "system", "cpu", fmt.Sprintf("cpu%d", id), "cpufreq", "scaling_cur_freq")
+	return float64(readUintFile(path)) / 1000
+}
+
+// parseCPUList 解析 "0-3,8,10-11" 形式的 CPU 列表
+func parseCPUList(list string) []int {
+	var result []int
+	for _, part := range strings.Split(strings.TrimSpace(list), ",") {
+		if part == "" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(lo)
+		if err != nil {
+			continue
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(hi); err != nil {
+				continue
+			}
+		}
+		for i := start; i <= end; i++ {
+			result = append(result, i)
+		}
+	}
+	sort.Ints(result)
+	return result
+}
+
+// readStringFile 读取文本文件内容，失败返回空字符串
+func readStringFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
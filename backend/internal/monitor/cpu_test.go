@@ -0,0 +1,52 @@
+package monitor
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+)
+
+func TestCPUIDs(t *testing.T) {
+	// cpu1 离线时 cpu.Times 不包含它，下标与编号不一致
+	times := []cpu.TimesStat{{CPU: "cpu0"}, {CPU: "cpu2"}, {CPU: "cpu3"}, {CPU: "unknown"}}
+	if ids := cpuIDs(times); !reflect.DeepEqual(ids, []int{0, 2, 3, 3}) {
+		t.Errorf("cpuIDs() = %v, want [0 2 3 3]", ids)
+	}
+}
+
+func TestReadCPUTopologyByID(t *testing.T) {
+	root := fakeSysfs(t)
+	cpuDir := filepath.Join(root, "devices", "system", "cpu")
+	writeFiles(t, cpuDir, map[string]string{
+		"cpu0/topology/physical_package_id":  "0",
+		"cpu0/topology/core_id":              "0",
+		"cpu0/topology/thread_siblings_list": "0",
+		"cpu1/topology/physical_package_id":  "0",
+		"cpu1/topology/core_id":              "1",
+		"cpu2/topology/physical_package_id":  "1",
+		"cpu2/topology/core_id":              "4",
+		"cpu2/topology/thread_siblings_list": "2-3",
+		"cpu2/cpufreq/cpuinfo_max_freq":      "3500000",
+		"cpu2/node1/cpulist":                 "2-3",
+	})
+
+	topology := readCPUTopology([]int{0, 2})
+	if len(topology) != 2 {
+		t.Fatalf("readCPUTopology() returned %d CPUs, want 2", len(topology))
+	}
+	if _, ok := topology[1]; ok {
+		t.Error("topology contains cpu1 which was not requested")
+	}
+	core := topology[2]
+	if core.ID != 2 || core.Socket != 1 || core.Core != 4 || core.NUMANode != 1 || core.MaxFreq != 3500 {
+		t.Errorf("cpu2 = %+v, want socket 1, core 4, node 1, max 3500 MHz", core)
+	}
+	if !reflect.DeepEqual(core.Siblings, []int{2, 3}) {
+		t.Errorf("cpu2 siblings = %v, want [2 3]", core.Siblings)
+	}
+	if core := topology[0]; core.Socket != 0 || core.NUMANode != -1 {
+		t.Errorf("cpu0 = %+v, want socket 0 without NUMA node", core)
+	}
+}
@@ -0,0 +1,268 @@
+package monitor
+
+import (
+	"context"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/config"
+	"github.com/dat-G/MLServer_Dash/backend/internal/models"
+)
+
+// dirWatcher 在后台周期性统计监控目录的占用
+type dirWatcher struct {
+	mu       sync.RWMutex
+	dirs     []*models.DirUsage
+	interval time.Duration
+	topN     int
+	history  int
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var watcher *dirWatcher
+
+// InitDirWatcher 启动目录占用统计
+// 扫描在独立的低 IO 优先级线程中进行，不会阻塞 GetSystemInfo；parent 取消后停止
+func InitDirWatcher(parent context.Context, cfg config.StorageConfig) {
+	if len(cfg.WatchDirs) == 0 {
+		return
+	}
+
+	interval := time.Duration(cfg.ScanInterval) * time.Second
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+	topN := cfg.TopN
+	if topN <= 0 {
+		topN = 10
+	}
+
+	w := &dirWatcher{
+		interval: interval,
+		topN:     topN,
+		history:  cfg.HistorySize,
+		done:     make(chan struct{}),
+	}
+	for _, dir := range cfg.WatchDirs {
+		w.dirs = append(w.dirs, &models.DirUsage{
+			Path:    expandHome(dir),
+			Top:     []models.DirEntryUsage{},
+			History: []models.DirUsageSample{},
+		})
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	w.cancel = cancel
+	watcher = w
+
+	go w.run(ctx)
+	log.Printf("Directory watcher started for %d dirs, interval: %v", len(w.dirs), interval)
+}
+
+// StopDirWatcher 取消正在进行的扫描并等待后台线程退出
+func StopDirWatcher() {
+	if watcher == nil {
+		return
+	}
+	watcher.cancel()
+	<-watcher.done
+}
+
+// GetDirUsage 返回监控目录的最新统计结果
+func GetDirUsage() []models.DirUsage {
+	if watcher == nil {
+		return []models.DirUsage{}
+	}
+
+	watcher.mu.RLock()
+	defer watcher.mu.RUnlock()
+
+	result := make([]models.DirUsage, 0, len(watcher.dirs))
+	for _, d := range watcher.dirs {
+		usage := *d
+		usage.Top = append([]models.DirEntryUsage(nil), d.Top...)
+		usage.History = append([]models.DirUsageSample(nil), d.History...)
+		result = append(result, usage)
+	}
+	return result
+}
+
+// run 扫描主循环
+func (w *dirWatcher) run(ctx context.Context) {
+	defer close(w.done)
+
+	// 降低当前线程的 CPU 和 IO 优先级。线程锁定后不再解锁，
+	// goroutine 退出时该线程随之销毁，优先级不会影响其他 goroutine
+	runtime.LockOSThread()
+	if err := lowerThreadPriority(); err != nil {
+		log.Printf("Directory watcher: failed to lower IO priority: %v", err)
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		for i := range w.dirs {
+			if ctx.Err() != nil {
+				return
+			}
+			w.scan(ctx, i)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scan 扫描单个监控目录并更新结果
+func (w *dirWatcher) scan(ctx context.Context, index int) {
+	w.mu.Lock()
+	path := w.dirs[index].Path
+	w.dirs[index].Scanning = true
+	w.mu.Unlock()
+
+	start := time.Now()
+	result, err := walkDir(ctx, path, w.topN)
+	if ctx.Err() != nil {
+		// 扫描被取消，保留上一次的结果
+		w.mu.Lock()
+		w.dirs[index].Scanning = false
+		w.mu.Unlock()
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	d := w.dirs[index]
+	d.Scanning = false
+	d.LastScan = &start
+	d.ScanDuration = time.Since(start).Seconds()
+	if err != nil {
+		d.Error = err.Error()
+		return
+	}
+
+	d.Error = ""
+	d.Size = result.size
+	d.SizeHuman = FormatBytes(result.size)
+	d.Files = result.files
+	d.Dirs = result.dirs
+	d.Top = result.top
+
+	d.History = append(d.History, models.DirUsageSample{
+		Timestamp: start,
+		Size:      result.size,
+		Files:     result.files,
+	})
+	if w.history > 0 && len(d.History) > w.history {
+		d.History = d.History[len(d.History)-w.history:]
+	}
+}
+
+// walkResult 单次目录扫描结果
+type walkResult struct {
+	size  uint64
+	files uint64
+	dirs  uint64
+	top   []models.DirEntryUsage
+}
+
+// walkDir 递归统计目录大小，按直接子项汇总并保留最大的 topN 项
+// 不跨越文件系统（与 du -x 一致），避免重复统计 Docker overlay 等挂载点
+func walkDir(ctx context.Context, root string, topN int) (walkResult, error) {
+	var result walkResult
+
+	rootInfo, err := os.Stat(root)
+	if err != nil {
+		return result, err
+	}
+	rootDev, _ := fileDevice(rootInfo)
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return result, err
+	}
+
+	children := make([]models.DirEntryUsage, 0, len(entries))
+	for _, entry := range entries {
+		child := models.DirEntryUsage{Name: entry.Name(), IsDir: entry.IsDir()}
+
+		err := filepath.WalkDir(filepath.Join(root, entry.Name()), func(path string, d fs.DirEntry, err error) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil {
+				// 无权限等错误跳过该项
+				if d != nil && d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			if dev, ok := fileDevice(info); ok && dev != rootDev {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+
+			child.Size += fileDiskUsage(info)
+			if d.IsDir() {
+				result.dirs++
+			} else {
+				child.Files++
+			}
+			return nil
+		})
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		if err != nil {
+			continue
+		}
+
+		child.SizeHuman = FormatBytes(child.Size)
+		result.size += child.Size
+		result.files += child.Files
+		children = append(children, child)
+	}
+
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].Size > children[j].Size
+	})
+	if len(children) > topN {
+		children = children[:topN]
+	}
+	result.top = children
+
+	return result, nil
+}
+
+// expandHome 展开路径开头的 ~
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
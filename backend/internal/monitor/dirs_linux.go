@@ -0,0 +1,43 @@
+package monitor
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+const (
+	ioprioClassIdle  = 3
+	ioprioClassShift = 13
+	ioprioWhoProcess = 1
+)
+
+// lowerThreadPriority 将当前线程设为 idle IO 调度类并降低 CPU 优先级
+// 调用方需要先执行 runtime.LockOSThread
+func lowerThreadPriority() error {
+	tid := syscall.Gettid()
+	if err := syscall.Setpriority(syscall.PRIO_PROCESS, tid, 19); err != nil {
+		return err
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOPRIO_SET,
+		ioprioWhoProcess, uintptr(tid), ioprioClassIdle<<ioprioClassShift)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// fileDiskUsage 返回文件实际占用的磁盘空间（与 du 一致）
+func fileDiskUsage(info fs.FileInfo) uint64 {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(st.Blocks) * 512
+	}
+	return uint64(info.Size())
+}
+
+// fileDevice 返回文件所在设备号
+func fileDevice(info fs.FileInfo) (uint64, bool) {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(st.Dev), true
+	}
+	return 0, false
+}
@@ -0,0 +1,20 @@
+//go:build !linux
+
+package monitor
+
+import "io/fs"
+
+// lowerThreadPriority 非 Linux 平台不调整优先级
+func lowerThreadPriority() error {
+	return nil
+}
+
+// fileDiskUsage 返回文件大小
+func fileDiskUsage(info fs.FileInfo) uint64 {
+	return uint64(info.Size())
+}
+
+// fileDevice 非 Linux 平台不区分设备
+func fileDevice(info fs.FileInfo) (uint64, bool) {
+	return 0, false
+}
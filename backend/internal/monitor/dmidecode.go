@@ -0,0 +1,183 @@
+package monitor
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/models"
+)
+
+// 内存条信息只在首次使用时读取一次，dmidecode 需要 root 权限
+var (
+	dimmOnce  sync.Once
+	dimmCache []models.MemoryModule
+)
+
+// GetMemoryModules 获取已安装内存条信息，无法读取时返回 nil
+func GetMemoryModules() []models.MemoryModule {
+	dimmOnce.Do(func() {
+		if _, err := exec.LookPath("dmidecode"); err != nil {
+			return
+		}
+		output, err := exec.Command("dmidecode", "-t", "17").Output()
+		if err != nil {
+			return
+		}
+		dimmCache = parseDmidecodeMemory(string(output))
+	})
+	return dimmCache
+}
+
+// parseDmidecodeMemory 解析 dmidecode -t 17 (Memory Device) 输出，跳过空插槽
+func parseDmidecodeMemory(output string) []models.MemoryModule {
+	var modules []models.MemoryModule
+	var current *models.MemoryModule
+	var totalWidth, dataWidth int
+
+	flush := func() {
+		if current != nil && current.Size > 0 {
+			current.SizeHuman = FormatBytes(current.Size)
+			current.ECC = dataWidth > 0 && totalWidth > dataWidth
+			modules = append(modules, *current)
+		}
+		current = nil
+		totalWidth, dataWidth = 0, 0
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "Memory Device" {
+			flush()
+			current = &models.MemoryModule{}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "Handle ") {
+			flush()
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if isDMIPlaceholder(value) {
+			continue
+		}
+
+		switch key {
+		case "Size":
+			current.Size = parseDMISize(value)
+		case "Locator":
+			current.Locator = value
+		case "Type":
+			current.Type = value
+		case "Speed":
+			current.Speed = parseDMISpeed(value)
+		case "Configured Memory Speed", "Configured Clock Speed":
+			current.ConfiguredSpeed = parseDMISpeed(value)
+		case "Manufacturer":
+			current.Manufacturer = value
+		case "Part Number":
+			current.PartNumber = value
+		case "Total Width":
+			totalWidth = parseDMISpeed(value)
+		case "Data Width":
+			dataWidth = parseDMISpeed(value)
+		}
+	}
+	flush()
+
+	return modules
+}
+
+// isDMIPlaceholder 判断 dmidecode 的占位值
+func isDMIPlaceholder(value string) bool {
+	switch strings.ToLower(value) {
+	case "", "unknown", "not specified", "not provided", "none", "no module installed",
+		"undefined", "to be filled by o.e.m.":
+		return true
+	}
+	return false
+}
+
+// parseDMISize 解析 "32 GB"、"16384 MB" 形式的容量
+func parseDMISize(value string) uint64 {
+	fields := strings.Fields(value)
+	if len(fields) != 2 {
+		return 0
+	}
+	n, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return 0
+	}
+	switch strings.ToUpper(fields[1]) {
+	case "KB":
+		return n << 10
+	case "MB":
+		return n << 20
+	case "GB":
+		return n << 30
+	case "TB":
+		return n << 40
+	}
+	return 0
+}
+
+// parseDMISpeed 解析 "3200 MT/s" 或 "2666 MHz" 形式的速率，也用于 "72 bits" 形式的位宽
+func parseDMISpeed(value string) int {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return 0
+	}
+	speed, _ := strconv.Atoi(fields[0])
+	return speed
+}
+
+// memoryModelString 生成内存型号描述，如 "Samsung DDR5 4800 MT/s (8 x 32.00 GB)"
+func memoryModelString(modules []models.MemoryModule) string {
+	if len(modules) == 0 {
+		return ""
+	}
+
+	first := modules[0]
+	uniform := true
+	for _, m := range modules[1:] {
+		if m.Manufacturer != first.Manufacturer || m.Type != first.Type ||
+			m.Speed != first.Speed || m.Size != first.Size || m.ECC != first.ECC {
+			uniform = false
+			break
+		}
+	}
+
+	var parts []string
+	if uniform && first.Manufacturer != "" {
+		parts = append(parts, first.Manufacturer)
+	}
+	if first.Type != "" {
+		parts = append(parts, first.Type)
+	}
+	if uniform && first.ECC {
+		parts = append(parts, "ECC")
+	}
+	speed := first.ConfiguredSpeed
+	if speed == 0 {
+		speed = first.Speed
+	}
+	if speed > 0 {
+		parts = append(parts, fmt.Sprintf("%d MT/s", speed))
+	}
+	if uniform {
+		parts = append(parts, fmt.Sprintf("(%d x %s)", len(modules), first.SizeHuman))
+	} else {
+		parts = append(parts, fmt.Sprintf("(%d modules)", len(modules)))
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,116 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/models"
+)
+
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestParseDmidecodeMemory(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    []models.MemoryModule
+		model   string
+	}{
+		{
+			// 服务器：两根 ECC 内存和两个空插槽，速率为 MT/s
+			fixture: "dmidecode-t17-server.txt",
+			want: []models.MemoryModule{
+				{
+					Locator: "DIMM_A1", Size: 32 << 30, SizeHuman: "32.00 GB", Type: "DDR4",
+					Speed: 3200, ConfiguredSpeed: 2933, Manufacturer: "Samsung",
+					PartNumber: "M393A4K40DB3-CWE", ECC: true,
+				},
+				{
+					Locator: "DIMM_B1", Size: 32 << 30, SizeHuman: "32.00 GB", Type: "DDR4",
+					Speed: 3200, ConfiguredSpeed: 2933, Manufacturer: "Samsung",
+					PartNumber: "M393A4K40DB3-CWE", ECC: true,
+				},
+			},
+			model: "Samsung DDR4 ECC 2933 MT/s (2 x 32.00 GB)",
+		},
+		{
+			// 旧版 dmidecode：容量为 MB，速率为 MHz，部分字段为 Unknown 或 OEM 占位值
+			fixture: "dmidecode-t17-desktop.txt",
+			want: []models.MemoryModule{
+				{
+					Locator: "ChannelA-DIMM0", Size: 16 << 30, SizeHuman: "16.00 GB", Type: "DDR3",
+					Speed: 1600, ConfiguredSpeed: 1333, Manufacturer: "Kingston",
+					PartNumber: "KHX1600C10D3/8G",
+				},
+				{
+					Locator: "ChannelB-DIMM0", Size: 16 << 30, SizeHuman: "16.00 GB", Type: "DDR3",
+				},
+			},
+			model: "DDR3 1333 MT/s (2 modules)",
+		},
+		{
+			// 同一台机器上混插 ECC 和非 ECC 内存
+			fixture: "dmidecode-t17-mixed-ecc.txt",
+			want: []models.MemoryModule{
+				{
+					Locator: "DIMM1", Size: 16 << 30, SizeHuman: "16.00 GB", Type: "DDR4",
+					Speed: 2666, ConfiguredSpeed: 2666, Manufacturer: "Micron",
+					PartNumber: "18ASF2G72AZ-2G6E1", ECC: true,
+				},
+				{
+					Locator: "DIMM2", Size: 16 << 30, SizeHuman: "16.00 GB", Type: "DDR4",
+					Speed: 2666, ConfiguredSpeed: 2666, Manufacturer: "Micron",
+					PartNumber: "16ATF2G64AZ-2G6E1",
+				},
+			},
+			model: "DDR4 2666 MT/s (2 modules)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			got := parseDmidecodeMemory(readFixture(t, tt.fixture))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDmidecodeMemory() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+			if model := memoryModelString(got); model != tt.model {
+				t.Errorf("memoryModelString() = %q, want %q", model, tt.model)
+			}
+		})
+	}
+}
+
+func TestParseDmidecodeMemoryEmpty(t *testing.T) {
+	// 无权限时 dmidecode 只输出头部
+	if got := parseDmidecodeMemory("# dmidecode 3.5\n/sys/firmware/dmi/tables/smbios_entry_point: Permission denied\n"); got != nil {
+		t.Errorf("parseDmidecodeMemory() = %+v, want nil", got)
+	}
+	if got := memoryModelString(nil); got != "" {
+		t.Errorf("memoryModelString(nil) = %q, want empty", got)
+	}
+}
+
+func TestParseDMISize(t *testing.T) {
+	tests := map[string]uint64{
+		"32 GB":    32 << 30,
+		"16384 MB": 16 << 30,
+		"512 kB":   512 << 10,
+		"1 TB":     1 << 40,
+		"32GB":     0,
+		"x GB":     0,
+		"8 PB":     0,
+	}
+	for in, want := range tests {
+		if got := parseDMISize(in); got != want {
+			t.Errorf("parseDMISize(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
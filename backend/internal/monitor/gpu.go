@@ -51,7 +51,7 @@ func GetGPUInfo() []models.GPUInfo {
 
 	// 使用 nvidia-smi --query-gpu 命令获取GPU信息
 	cmd := exec.Command("nvidia-smi",
-		"--query-gpu=name,utilization.gpu,utilization.memory,temperature.gpu,power.draw,power.limit,enforced.power.limit,power.default_limit,memory.total,memory.used,memory.free",
+		"--query-gpu=name,utilization.gpu,utilization.memory,temperature.gpu,power.draw,power.limit,enforced.power.limit,power.default_limit,memory.total,memory.used,memory.free,index,uuid",
 		"--format=csv,noheader,nounits")
 
 	output, err := cmd.Output()
@@ -60,7 +60,9 @@ func GetGPUInfo() []models.GPUInfo {
 		return []models.GPUInfo{}
 	}
 
-	return parseGPUInfoCSV(string(output))
+	gpus := parseGPUInfoCSV(string(output))
+	attachGPUProcesses(gpus)
+	return gpus
 }
 
 // getGPUInfoJSON 使用 JSON 格式获取 GPU 信息
@@ -110,7 +112,7 @@ func parseGPUInfoCSV(output string) []models.GPUInfo {
 		}
 
 		// 解析每个字段
-		// name,utilization.gpu,utilization.memory,temperature.gpu,power.draw,power.limit,enforced.power.limit,power.default_limit,memory.total,memory.used,memory.free
+		// name,utilization.gpu,utilization.memory,temperature.gpu,power.draw,power.limit,enforced.power.limit,power.default_limit,memory.total,memory.used,memory.free,index,uuid
 		name := strings.TrimSpace(fields[0])
 		utilStr := strings.TrimSpace(fields[1])
 		_ = strings.TrimSpace(fields[2]) // memUtilStr 未使用
@@ -122,6 +124,12 @@ func parseGPUInfoCSV(output string) []models.GPUInfo {
 		memTotalStr := strings.TrimSpace(fields[8])
 		memUsedStr := strings.TrimSpace(fields[9])
 		memFreeStr := strings.TrimSpace(fields[10])
+		index := len(gpus)
+		var uuid string
+		if len(fields) >= 13 {
+			index, _ = strconv.Atoi(strings.TrimSpace(fields[11]))
+			uuid = strings.TrimSpace(fields[12])
+		}
 
 		utilization, _ := strconv.ParseFloat(strings.TrimSuffix(utilStr, " %"), 64)
 		temperature, _ := strconv.Atoi(tempStr)
@@ -165,6 +173,8 @@ func parseGPUInfoCSV(output string) []models.GPUInfo {
 			PowerLimit:         int(powerLimit),
 			EnforcedPowerLimit: int(enforcedPowerLimit),
 			PowerDefaultLimit:  int(powerDefaultLimit),
+			Index:              index,
+			UUID:               uuid,
 		})
 	}
 
@@ -0,0 +1,99 @@
+package monitor
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/process"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/models"
+)
+
+// containerIDPattern cgroup 路径中的容器 ID（docker、containerd、cri-o 均为 64 位十六进制）
+var containerIDPattern = regexp.MustCompile(`[0-9a-f]{64}`)
+
+// attachGPUProcesses 查询每块 GPU 上的计算进程，按 UUID 归属到对应的 GPU
+func attachGPUProcesses(gpus []models.GPUInfo) {
+	if len(gpus) == 0 {
+		return
+	}
+
+	cmd := exec.Command("nvidia-smi",
+		"--query-compute-apps=gpu_uuid,pid,process_name,used_memory",
+		"--format=csv,noheader,nounits")
+	output, err := cmd.Output()
+	if err != nil {
+		return
+	}
+
+	byUUID := make(map[string]*models.GPUInfo, len(gpus))
+	for i := range gpus {
+		byUUID[gpus[i].UUID] = &gpus[i]
+	}
+	for _, proc := range parseGPUProcessesCSV(string(output)) {
+		if gpu, ok := byUUID[proc.uuid]; ok {
+			gpu.Processes = append(gpu.Processes, proc.GPUProcess)
+		}
+	}
+}
+
+// gpuProcess nvidia-smi 输出的一行，uuid 为进程所在的 GPU
+type gpuProcess struct {
+	models.GPUProcess
+	uuid string
+}
+
+// parseGPUProcessesCSV 解析 --query-compute-apps 的 CSV 输出，并补充进程用户和所在容器
+func parseGPUProcessesCSV(output string) []gpuProcess {
+	var procs []gpuProcess
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		// gpu_uuid,pid,process_name,used_memory
+		fields := strings.Split(line, ",")
+		if len(fields) < 4 {
+			continue
+		}
+		pid, err := strconv.ParseInt(strings.TrimSpace(fields[1]), 10, 32)
+		if err != nil {
+			continue
+		}
+		usedMiB, _ := strconv.ParseUint(strings.TrimSpace(fields[3]), 10, 64)
+
+		p := gpuProcess{
+			GPUProcess: models.GPUProcess{
+				PID:         int32(pid),
+				Name:        filepath.Base(strings.TrimSpace(fields[2])),
+				UsedMemory:  usedMiB * 1024 * 1024,
+				ContainerID: processContainerID(int32(pid)),
+			},
+			uuid: strings.TrimSpace(fields[0]),
+		}
+		if proc, err := process.NewProcess(int32(pid)); err == nil {
+			p.User, _ = proc.Username()
+		}
+		procs = append(procs, p)
+	}
+	return procs
+}
+
+// processContainerID 从 /proc/<pid>/cgroup 中读取进程所在容器的短 ID，不在容器中时返回空
+// 容器内的进程在宿主机 PID 命名空间中才能查到，非 Linux 系统总是返回空
+func processContainerID(pid int32) string {
+	f, err := os.Open(filepath.Join(procRoot, strconv.Itoa(int(pid)), "cgroup"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if id := containerIDPattern.FindString(scanner.Text()); id != "" {
+			return id[:12]
+		}
+	}
+	return ""
+}
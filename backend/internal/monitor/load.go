@@ -0,0 +1,176 @@
+package monitor
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/load"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/models"
+)
+
+// procRoot procfs 挂载点
+var procRoot = "/proc"
+
+// procStatCounters /proc/stat 中的累计计数
+type procStatCounters struct {
+	ctxt  uint64
+	intr  uint64
+	valid bool
+}
+
+// 上一次的 /proc/stat 计数，用于计算每秒速率
+var (
+	loadMu        sync.Mutex
+	lastProcStat  procStatCounters
+	lastProcStatT time.Time
+)
+
+// GetLoadInfo 获取负载均值、运行队列长度以及上下文切换和中断速率
+func GetLoadInfo() *models.LoadInfo {
+	avg, err := load.Avg()
+	if err != nil {
+		return nil
+	}
+
+	info := &models.LoadInfo{
+		Load1:  avg.Load1,
+		Load5:  avg.Load5,
+		Load15: avg.Load15,
+	}
+
+	counters, running, blocked := readProcStat()
+	info.ProcsRunning = running
+	info.ProcsBlocked = blocked
+	if !counters.valid {
+		return info
+	}
+
+	loadMu.Lock()
+	defer loadMu.Unlock()
+
+	now := time.Now()
+	if lastProcStat.valid && !lastProcStatT.IsZero() {
+		timeDiff := now.Sub(lastProcStatT).Seconds()
+		if timeDiff > 0 && counters.ctxt >= lastProcStat.ctxt && counters.intr >= lastProcStat.intr {
+			ctxt := float64(counters.ctxt-lastProcStat.ctxt) / timeDiff
+			intr := float64(counters.intr-lastProcStat.intr) / timeDiff
+			info.ContextSwitches = &ctxt
+			info.Interrupts = &intr
+		}
+	}
+	lastProcStat = counters
+	lastProcStatT = now
+
+	return info
+}
+
+// readProcStat 读取 /proc/stat 中的 ctxt、intr、procs_running 和 procs_blocked
+func readProcStat() (procStatCounters, int, int) {
+	var counters procStatCounters
+	var running, blocked int
+
+	f, err := os.Open(filepath.Join(procRoot, "stat"))
+	if err != nil {
+		return counters, 0, 0
+	}
+	defer f.Close()
+
+	var hasCtxt, hasIntr bool
+	scanner := bufio.NewScanner(f)
+	// intr 行包含每个中断号的计数，可能很长
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "ctxt":
+			counters.ctxt, err = strconv.ParseUint(fields[1], 10, 64)
+			hasCtxt = err == nil
+		case "intr":
+			// 第一个数为所有中断的总数
+			counters.intr, err = strconv.ParseUint(fields[1], 10, 64)
+			hasIntr = err == nil
+		case "procs_running":
+			running, _ = strconv.Atoi(fields[1])
+		case "procs_blocked":
+			blocked, _ = strconv.Atoi(fields[1])
+		}
+	}
+	counters.valid = hasCtxt && hasIntr
+
+	return counters, running, blocked
+}
+
+// GetPressureInfo 读取 Linux PSI (/proc/pressure/*)
+// 内核未开启 PSI 或非 Linux 平台时返回 nil
+func GetPressureInfo() *models.PressureInfo {
+	info := &models.PressureInfo{
+		CPU:    readPressure("cpu"),
+		Memory: readPressure("memory"),
+		IO:     readPressure("io"),
+	}
+	if info.CPU == nil && info.Memory == nil && info.IO == nil {
+		return nil
+	}
+	return info
+}
+
+// readPressure 解析单个 PSI 文件，格式如下：
+//
+//	some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func readPressure(resource string) *models.PressureResource {
+	data, err := os.ReadFile(filepath.Join(procRoot, "pressure", resource))
+	if err != nil {
+		return nil
+	}
+
+	var result models.PressureResource
+	var found bool
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var stat models.PressureStat
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			switch key {
+			case "avg10":
+				stat.Avg10, _ = strconv.ParseFloat(value, 64)
+			case "avg60":
+				stat.Avg60, _ = strconv.ParseFloat(value, 64)
+			case "avg300":
+				stat.Avg300, _ = strconv.ParseFloat(value, 64)
+			case "total":
+				stat.Total, _ = strconv.ParseUint(value, 10, 64)
+			}
+		}
+
+		switch fields[0] {
+		case "some":
+			result.Some = &stat
+			found = true
+		case "full":
+			result.Full = &stat
+			found = true
+		}
+	}
+
+	if !found {
+		return nil
+	}
+	return &result
+}
@@ -0,0 +1,195 @@
+package monitor
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/models"
+)
+
+// sysRoot sysfs 挂载点
+var sysRoot = "/sys"
+
+// 上一次的换入/换出累计字节数，用于计算速率
+var (
+	swapMu       sync.Mutex
+	lastSwapIn   uint64
+	lastSwapOut  uint64
+	lastSwapTime time.Time
+)
+
+// GetSwapInfo 获取交换分区使用情况和换入/换出速率
+func GetSwapInfo() *models.SwapInfo {
+	swap, err := mem.SwapMemory()
+	if err != nil {
+		return nil
+	}
+
+	info := &models.SwapInfo{
+		Total:      swap.Total,
+		Used:       swap.Used,
+		Free:       swap.Free,
+		Percent:    swap.UsedPercent,
+		TotalHuman: FormatBytes(swap.Total),
+		UsedHuman:  FormatBytes(swap.Used),
+	}
+
+	swapMu.Lock()
+	defer swapMu.Unlock()
+
+	now := time.Now()
+	if !lastSwapTime.IsZero() {
+		timeDiff := now.Sub(lastSwapTime).Seconds()
+		if timeDiff > 0 && swap.Sin >= lastSwapIn && swap.Sout >= lastSwapOut {
+			in := float64(swap.Sin-lastSwapIn) / timeDiff
+			out := float64(swap.Sout-lastSwapOut) / timeDiff
+			info.SwapIn = &in
+			info.SwapOut = &out
+		}
+	}
+	lastSwapIn = swap.Sin
+	lastSwapOut = swap.Sout
+	lastSwapTime = now
+
+	return info
+}
+
+// GetShmInfo 获取 /dev/shm 使用情况（PyTorch DataLoader 多进程共享内存）
+func GetShmInfo() *models.ShmInfo {
+	usage, err := disk.Usage("/dev/shm")
+	if err != nil || usage.Total == 0 {
+		return nil
+	}
+	return &models.ShmInfo{
+		Total:      usage.Total,
+		Used:       usage.Used,
+		Percent:    usage.UsedPercent,
+		TotalHuman: FormatBytes(usage.Total),
+		UsedHuman:  FormatBytes(usage.Used),
+	}
+}
+
+// GetHugePages 读取各尺寸大页内存池 (/sys/kernel/mm/hugepages)
+func GetHugePages() []models.HugePagePool {
+	base := filepath.Join(sysRoot, "kernel", "mm", "hugepages")
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		return nil
+	}
+
+	var pools []models.HugePagePool
+	for _, entry := range entries {
+		// 目录名形如 hugepages-2048kB
+		sizeStr := strings.TrimSuffix(strings.TrimPrefix(entry.Name(), "hugepages-"), "kB")
+		sizeKB, err := strconv.ParseUint(sizeStr, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		dir := filepath.Join(base, entry.Name())
+		pool := models.HugePagePool{
+			PageSize:      sizeKB * 1024,
+			PageSizeHuman: FormatBytes(sizeKB * 1024),
+			Total:         readUintFile(filepath.Join(dir, "nr_hugepages")),
+			Free:          readUintFile(filepath.Join(dir, "free_hugepages")),
+			Reserved:      readUintFile(filepath.Join(dir, "resv_hugepages")),
+			Surplus:       readUintFile(filepath.Join(dir, "surplus_hugepages")),
+		}
+		pools = append(pools, pool)
+	}
+
+	sort.Slice(pools, func(i, j int) bool {
+		return pools[i].PageSize < pools[j].PageSize
+	})
+	return pools
+}
+
+// GetNUMAMemory 读取每个 NUMA 节点的内存使用 (/sys/devices/system/node/node*/meminfo)
+// 单节点机器也会返回 node0
+func GetNUMAMemory() []models.NUMANodeMemory {
+	base := filepath.Join(sysRoot, "devices", "system", "node")
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		return nil
+	}
+
+	var nodes []models.NUMANodeMemory
+	for _, entry := range entries {
+		id, err := strconv.Atoi(strings.TrimPrefix(entry.Name(), "node"))
+		if err != nil || !strings.HasPrefix(entry.Name(), "node") {
+			continue
+		}
+
+		values := readNodeMeminfo(filepath.Join(base, entry.Name(), "meminfo"))
+		total := values["MemTotal"]
+		if total == 0 {
+			continue
+		}
+		free := values["MemFree"]
+		used := total - free
+
+		nodes = append(nodes, models.NUMANodeMemory{
+			Node:       id,
+			Total:      total,
+			Used:       used,
+			Free:       free,
+			FilePages:  values["FilePages"],
+			Percent:    float64(used) / float64(total) * 100,
+			TotalHuman: FormatBytes(total),
+			UsedHuman:  FormatBytes(used),
+		})
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Node < nodes[j].Node
+	})
+	return nodes
+}
+
+// readNodeMeminfo 解析节点 meminfo，行格式为 "Node 0 MemTotal:  65843380 kB"，返回字节数
+func readNodeMeminfo(path string) map[string]uint64 {
+	values := make(map[string]uint64)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return values
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[3], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) > 4 && fields[4] == "kB" {
+			value *= 1024
+		}
+		values[strings.TrimSuffix(fields[2], ":")] = value
+	}
+
+	return values
+}
+
+// readUintFile 读取只包含一个整数的 sysfs 文件，失败返回 0
+func readUintFile(path string) uint64 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	value, _ := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	return value
+}
@@ -0,0 +1,249 @@
+package monitor
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/config"
+	"github.com/dat-G/MLServer_Dash/backend/internal/models"
+)
+
+// networkOptions 网络接口展示选项，配置热加载时更新
+var (
+	networkOptionsMu sync.RWMutex
+	networkOptions   config.NetworkConfig
+)
+
+// pciIDsPaths pci.ids 数据库的常见位置
+var pciIDsPaths = []string{
+	"/usr/share/hwdata/pci.ids",
+	"/usr/share/misc/pci.ids",
+	"/usr/share/pci.ids",
+}
+
+// PCI 设备名称缓存，键为 "vendor:device"
+var (
+	pciNameMu    sync.Mutex
+	pciNameCache = make(map[string]string)
+)
+
+// virtualPrefixes 无法读取 sysfs 时按名称判断虚拟接口
+var virtualPrefixes = []string{"veth", "docker", "br-", "virbr", "cni", "flannel", "cali", "vxlan", "tun", "tap"}
+
+// InitNetwork 设置网络接口展示选项，可在运行中再次调用
+func InitNetwork(cfg config.NetworkConfig) {
+	networkOptionsMu.Lock()
+	defer networkOptionsMu.Unlock()
+	networkOptions = cfg
+}
+
+// nicDetails 从 /sys/class/net/<if> 读取的网卡属性
+type nicDetails struct {
+	model     string
+	driver    string
+	speed     *int
+	duplex    string
+	mtu       int
+	operState string
+	virtual   bool
+	bridge    bool
+	master    string
+}
+
+// readNICDetails 读取网卡的链路速率、双工、驱动、MTU、状态和型号
+func readNICDetails(name string) nicDetails {
+	dir := filepath.Join(sysRoot, "class", "net", name)
+	var d nicDetails
+
+	// 链路未连接时读取 speed 会返回错误或 -1
+	if speed, err := strconv.Atoi(readStringFile(filepath.Join(dir, "speed"))); err == nil && speed > 0 {
+		d.speed = &speed
+	}
+	if duplex := readStringFile(filepath.Join(dir, "duplex")); duplex != "unknown" {
+		d.duplex = duplex
+	}
+	d.mtu, _ = strconv.Atoi(readStringFile(filepath.Join(dir, "mtu")))
+	d.operState = readStringFile(filepath.Join(dir, "operstate"))
+
+	if target, err := os.Readlink(filepath.Join(dir, "device", "driver")); err == nil {
+		d.driver = filepath.Base(target)
+	}
+	if target, err := os.Readlink(filepath.Join(dir, "master")); err == nil {
+		d.master = filepath.Base(target)
+	}
+	_, err := os.Stat(filepath.Join(dir, "bridge"))
+	d.bridge = err == nil
+
+	d.virtual = isVirtualNIC(name, dir)
+
+	// 只有 PCI 设备才能从 pci.ids 查询型号
+	if subsystem, err := os.Readlink(filepath.Join(dir, "device", "subsystem")); err == nil && filepath.Base(subsystem) == "pci" {
+		vendor := strings.TrimPrefix(readStringFile(filepath.Join(dir, "device", "vendor")), "0x")
+		device := strings.TrimPrefix(readStringFile(filepath.Join(dir, "device", "device")), "0x")
+		d.model = lookupPCIName(vendor, device)
+	}
+
+	return d
+}
+
+// isVirtualNIC 判断是否为容器/虚拟机使用的虚拟接口
+// bond 和 VLAN 虽然也位于 /sys/devices/virtual，但通常承载真实流量，不视为虚拟接口
+func isVirtualNIC(name, dir string) bool {
+	target, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		for _, prefix := range virtualPrefixes {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !strings.Contains(target, "/devices/virtual/") {
+		return false
+	}
+	if _, err := os.Stat(filepath.Join(dir, "bonding")); err == nil {
+		return false
+	}
+	if _, err := os.Stat(filepath.Join(procRoot, "net", "vlan", name)); err == nil {
+		return false
+	}
+	return true
+}
+
+// lookupPCIName 在 pci.ids 中查询 "厂商 设备" 名称，找不到时返回空字符串
+func lookupPCIName(vendor, device string) string {
+	if vendor == "" || device == "" {
+		return ""
+	}
+	key := strings.ToLower(vendor + ":" + device)
+
+	pciNameMu.Lock()
+	defer pciNameMu.Unlock()
+
+	if name, ok := pciNameCache[key]; ok {
+		return name
+	}
+
+	name := ""
+	for _, path := range pciIDsPaths {
+		if name = scanPCIIDs(path, strings.ToLower(vendor), strings.ToLower(device)); name != "" {
+			break
+		}
+	}
+	pciNameCache[key] = name
+	return name
+}
+
+// scanPCIIDs 扫描 pci.ids 文件，格式如下：
+//
+//	8086  Intel Corporation
+//		1593  Ethernet Controller E810-C for SFP
+func scanPCIIDs(path, vendor, device string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	var vendorName string
+	inVendor := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		if line[0] != '\t' {
+			// 厂商行
+			if inVendor {
+				break
+			}
+			id, name, ok := strings.Cut(line, "  ")
+			if ok && strings.ToLower(id) == vendor {
+				inVendor = true
+				vendorName = strings.TrimSpace(name)
+			}
+			continue
+		}
+
+		// 设备行只有一个制表符，子系统行有两个
+		if !inVendor || strings.HasPrefix(line, "\t\t") {
+			continue
+		}
+		id, name, ok := strings.Cut(strings.TrimPrefix(line, "\t"), "  ")
+		if ok && strings.ToLower(id) == device {
+			return vendorName + " " + strings.TrimSpace(name)
+		}
+	}
+
+	return ""
+}
+
+// classifyAddrs 按地址族区分接口地址，地址保持 CIDR 格式 (如 192.168.1.2/24)
+func classifyAddrs(addrs []string) (ipv4, ipv6 []string) {
+	for _, addr := range addrs {
+		ip, _, err := net.ParseCIDR(addr)
+		if err != nil {
+			if ip = net.ParseIP(addr); ip == nil {
+				continue
+			}
+		}
+		if ip.To4() != nil {
+			ipv4 = append(ipv4, addr)
+		} else {
+			ipv6 = append(ipv6, addr)
+		}
+	}
+	return ipv4, ipv6
+}
+
+// applyNetworkOptions 根据配置隐藏虚拟接口或将其归入所属网桥
+// 先归组再隐藏：同时开启 hideVirtual 和 groupVirtual 时，有成员的网桥代表归入的虚拟接口继续列出，
+// 其余虚拟接口（未挂在网桥上的 veth、没有成员的网桥等）被隐藏
+func applyNetworkOptions(interfaces []models.NetworkInterface) []models.NetworkInterface {
+	networkOptionsMu.RLock()
+	opts := networkOptions
+	networkOptionsMu.RUnlock()
+
+	if !opts.HideVirtual && !opts.GroupVirtual {
+		return interfaces
+	}
+
+	members := make(map[string][]string)
+	grouped := make(map[string]bool)
+	if opts.GroupVirtual {
+		bridges := make(map[string]bool)
+		for _, iface := range interfaces {
+			if iface.Bridge {
+				bridges[iface.Name] = true
+			}
+		}
+		for _, iface := range interfaces {
+			if iface.Virtual && iface.Master != "" && bridges[iface.Master] {
+				members[iface.Master] = append(members[iface.Master], iface.Name)
+				grouped[iface.Name] = true
+			}
+		}
+	}
+
+	result := make([]models.NetworkInterface, 0, len(interfaces))
+	for _, iface := range interfaces {
+		if grouped[iface.Name] {
+			continue
+		}
+		if m, ok := members[iface.Name]; ok {
+			iface.Members = m
+		} else if opts.HideVirtual && iface.Virtual {
+			continue
+		}
+		result = append(result, iface)
+	}
+	return result
+}
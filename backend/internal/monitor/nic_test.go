@@ -0,0 +1,55 @@
+package monitor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/config"
+	"github.com/dat-G/MLServer_Dash/backend/internal/models"
+)
+
+func TestApplyNetworkOptions(t *testing.T) {
+	interfaces := []models.NetworkInterface{
+		{Name: "eth0"},
+		{Name: "docker0", Virtual: true, Bridge: true},
+		{Name: "veth1", Virtual: true, Master: "docker0"},
+		{Name: "veth2", Virtual: true, Master: "docker0"},
+		{Name: "br-empty", Virtual: true, Bridge: true},
+		{Name: "veth3", Virtual: true},
+	}
+	tests := []struct {
+		name    string
+		opts    config.NetworkConfig
+		want    []string
+		members []string // docker0 的成员
+	}{
+		{"none", config.NetworkConfig{}, []string{"eth0", "docker0", "veth1", "veth2", "br-empty", "veth3"}, nil},
+		{"hide", config.NetworkConfig{HideVirtual: true}, []string{"eth0"}, nil},
+		{"group", config.NetworkConfig{GroupVirtual: true}, []string{"eth0", "docker0", "br-empty", "veth3"}, []string{"veth1", "veth2"}},
+		// 先归组再隐藏：有成员的网桥保留，其余虚拟接口隐藏
+		{"hide and group", config.NetworkConfig{HideVirtual: true, GroupVirtual: true}, []string{"eth0", "docker0"}, []string{"veth1", "veth2"}},
+	}
+
+	defer InitNetwork(config.NetworkConfig{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			InitNetwork(tt.opts)
+			result := applyNetworkOptions(interfaces)
+
+			var names []string
+			var members []string
+			for _, iface := range result {
+				names = append(names, iface.Name)
+				if iface.Name == "docker0" {
+					members = iface.Members
+				}
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("interfaces = %v, want %v", names, tt.want)
+			}
+			if !reflect.DeepEqual(members, tt.members) {
+				t.Errorf("docker0 members = %v, want %v", members, tt.members)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/models"
+)
+
+// KillProcess 向进程发送结束信号
+// signal 为 "term"（默认，SIGTERM）或 "kill"（SIGKILL）
+func KillProcess(pid int32, signal string) models.ActionResponse {
+	if pid <= 1 || int(pid) == os.Getpid() {
+		return models.ActionResponse{
+			Success: false,
+			Message: fmt.Sprintf("Refusing to signal process %d", pid),
+		}
+	}
+
+	proc, err := process.NewProcess(pid)
+	if err != nil {
+		return models.ActionResponse{
+			Success: false,
+			Message: fmt.Sprintf("Process %d not found", pid),
+		}
+	}
+
+	switch signal {
+	case "", "term":
+		err = proc.Terminate()
+	case "kill":
+		err = proc.Kill()
+	default:
+		return models.ActionResponse{
+			Success: false,
+			Message: fmt.Sprintf("Unknown signal: %s", signal),
+		}
+	}
+	if err != nil {
+		return models.ActionResponse{
+			Success: false,
+			Message: err.Error(),
+		}
+	}
+
+	return models.ActionResponse{
+		Success: true,
+		Message: fmt.Sprintf("Signal %s sent to process %d", signalName(signal), pid),
+	}
+}
+
+// signalName 信号名称
+func signalName(signal string) string {
+	if signal == "kill" {
+		return "SIGKILL"
+	}
+	return "SIGTERM"
+}
+
+// 上一次采集时各进程的 CPU 时间（秒），用于计算占用
+var (
+	processMu       sync.Mutex
+	lastProcessCPU  = make(map[int32]float64)
+	lastProcessTime time.Time
+)
+
+// GetProcesses 返回按 CPU 占用排序的前 limit 个进程
+// CPU 占用按两次调用之间的差值计算，首次调用时为 0
+func GetProcesses(limit int) []models.ProcessInfo {
+	procs, err := process.Processes()
+	if err != nil {
+		return []models.ProcessInfo{}
+	}
+
+	processMu.Lock()
+	defer processMu.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(lastProcessTime).Seconds()
+	current := make(map[int32]float64, len(procs))
+	result := make([]models.ProcessInfo, 0, len(procs))
+
+	for _, p := range procs {
+		times, err := p.Times()
+		if err != nil {
+			// 进程已退出或无权限
+			continue
+		}
+		total := times.User + times.System
+		current[p.Pid] = total
+
+		info := models.ProcessInfo{PID: p.Pid}
+		if last, ok := lastProcessCPU[p.Pid]; ok && !lastProcessTime.IsZero() && elapsed > 0 && total >= last {
+			info.CPUPercent = (total - last) / elapsed * 100
+		}
+		result = append(result, info)
+	}
+
+	lastProcessCPU = current
+	lastProcessTime = now
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CPUPercent > result[j].CPUPercent
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+
+	// 只为返回的进程读取详细信息
+	for i := range result {
+		fillProcessDetails(&result[i])
+	}
+	return result
+}
+
+// fillProcessDetails 读取进程名称、用户、内存等信息，读取失败的字段保持零值
+func fillProcessDetails(info *models.ProcessInfo) {
+	p, err := process.NewProcess(info.PID)
+	if err != nil {
+		return
+	}
+	info.Name, _ = p.Name()
+	info.Username, _ = p.Username()
+	if status, err := p.Status(); err == nil && len(status) > 0 {
+		info.Status = status[0]
+	}
+	if mem, err := p.MemoryInfo(); err == nil {
+		info.MemoryRSS = mem.RSS
+	}
+	info.MemoryPercent, _ = p.MemoryPercent()
+	info.Threads, _ = p.NumThreads()
+	info.Cmdline, _ = p.Cmdline()
+	info.CreateTime, _ = p.CreateTime()
+}
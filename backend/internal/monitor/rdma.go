@@ -0,0 +1,137 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/models"
+)
+
+// rdmaCounters 端口累计计数
+type rdmaCounters struct {
+	xmitBytes uint64
+	rcvBytes  uint64
+}
+
+// 上一次的 RDMA 端口计数，用于计算速率
+var (
+	rdmaMu        sync.Mutex
+	lastRDMAStats = make(map[string]rdmaCounters)
+	lastRDMATime  time.Time
+)
+
+// GetRDMAInfo 读取 InfiniBand/RoCE 端口状态和计数 (/sys/class/infiniband/*/ports/*)
+// 没有 RDMA 设备时返回 nil
+func GetRDMAInfo() []models.RDMAPort {
+	base := filepath.Join(sysRoot, "class", "infiniband")
+	devices, err := os.ReadDir(base)
+	if err != nil {
+		return nil
+	}
+
+	rdmaMu.Lock()
+	defer rdmaMu.Unlock()
+
+	currentTime := time.Now()
+	var ports []models.RDMAPort
+
+	for _, device := range devices {
+		portsDir := filepath.Join(base, device.Name(), "ports")
+		entries, err := os.ReadDir(portsDir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			portNum, err := strconv.Atoi(entry.Name())
+			if err != nil {
+				continue
+			}
+
+			port := readRDMAPort(filepath.Join(portsDir, entry.Name()))
+			port.Device = device.Name()
+			port.Port = portNum
+
+			// 计算速度（如果有上次数据），计数器回绕或复位时跳过
+			key := device.Name() + "/" + entry.Name()
+			if last, exists := lastRDMAStats[key]; exists && !lastRDMATime.IsZero() {
+				timeDiff := currentTime.Sub(lastRDMATime).Seconds()
+				if timeDiff > 0 {
+					if port.XmitBytes >= last.xmitBytes {
+						su := float64(port.XmitBytes-last.xmitBytes) / timeDiff
+						port.SpeedUp = &su
+					}
+					if port.RcvBytes >= last.rcvBytes {
+						sd := float64(port.RcvBytes-last.rcvBytes) / timeDiff
+						port.SpeedDown = &sd
+					}
+				}
+			}
+			lastRDMAStats[key] = rdmaCounters{xmitBytes: port.XmitBytes, rcvBytes: port.RcvBytes}
+
+			ports = append(ports, port)
+		}
+	}
+
+	lastRDMATime = currentTime
+
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Device != ports[j].Device {
+			return ports[i].Device < ports[j].Device
+		}
+		return ports[i].Port < ports[j].Port
+	})
+	return ports
+}
+
+// readRDMAPort 读取单个端口目录
+func readRDMAPort(dir string) models.RDMAPort {
+	port := models.RDMAPort{
+		State:     stripRDMAStateCode(readStringFile(filepath.Join(dir, "state"))),
+		PhysState: stripRDMAStateCode(readStringFile(filepath.Join(dir, "phys_state"))),
+		Rate:      readStringFile(filepath.Join(dir, "rate")),
+		LinkLayer: readStringFile(filepath.Join(dir, "link_layer")),
+	}
+	port.RateGbps = parseRDMARate(port.Rate)
+
+	// RoCE 端口关联的以太网接口
+	if ndev := readStringFile(filepath.Join(dir, "gid_attrs", "ndevs", "0")); ndev != "" {
+		port.NetDev = ndev
+	}
+
+	counters := filepath.Join(dir, "counters")
+	// port_xmit_data / port_rcv_data 的单位是 4 字节
+	port.XmitBytes = readUintFile(filepath.Join(counters, "port_xmit_data")) * 4
+	port.RcvBytes = readUintFile(filepath.Join(counters, "port_rcv_data")) * 4
+	port.XmitPackets = readUintFile(filepath.Join(counters, "port_xmit_packets"))
+	port.RcvPackets = readUintFile(filepath.Join(counters, "port_rcv_packets"))
+	port.RcvErrors = readUintFile(filepath.Join(counters, "port_rcv_errors"))
+	port.XmitDiscards = readUintFile(filepath.Join(counters, "port_xmit_discards"))
+	port.SymbolErrors = readUintFile(filepath.Join(counters, "symbol_error"))
+	port.LinkDowned = readUintFile(filepath.Join(counters, "link_downed"))
+
+	return port
+}
+
+// stripRDMAStateCode 去掉状态前的数字编码，如 "4: ACTIVE" -> "ACTIVE"
+func stripRDMAStateCode(state string) string {
+	if _, name, ok := strings.Cut(state, ":"); ok {
+		return strings.TrimSpace(name)
+	}
+	return state
+}
+
+// parseRDMARate 解析 "100 Gb/sec (4X EDR)" 形式的速率，返回 Gb/s
+func parseRDMARate(rate string) float64 {
+	fields := strings.Fields(rate)
+	if len(fields) == 0 {
+		return 0
+	}
+	value, _ := strconv.ParseFloat(fields[0], 64)
+	return value
+}
@@ -0,0 +1,207 @@
+package monitor
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/models"
+)
+
+// fakeSysfs 将 sysRoot 指向临时目录并清空 RDMA 速率状态，测试结束后恢复
+func fakeSysfs(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	oldRoot := sysRoot
+	sysRoot = root
+	resetRDMAState()
+	t.Cleanup(func() {
+		sysRoot = oldRoot
+		resetRDMAState()
+	})
+	return root
+}
+
+func resetRDMAState() {
+	rdmaMu.Lock()
+	defer rdmaMu.Unlock()
+	lastRDMAStats = make(map[string]rdmaCounters)
+	lastRDMATime = time.Time{}
+}
+
+// writeFiles 在 dir 下写入文件，键为相对路径
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// rewindRDMA 将上一次采样时间往前调 d，使两次采样的间隔确定
+func rewindRDMA(d time.Duration) {
+	rdmaMu.Lock()
+	defer rdmaMu.Unlock()
+	lastRDMATime = lastRDMATime.Add(-d)
+}
+
+func portDir(root, device, port string) string {
+	return filepath.Join(root, "class", "infiniband", device, "ports", port)
+}
+
+func TestGetRDMAInfoNoDevices(t *testing.T) {
+	fakeSysfs(t)
+	if ports := GetRDMAInfo(); ports != nil {
+		t.Errorf("GetRDMAInfo() = %+v, want nil", ports)
+	}
+}
+
+func TestGetRDMAInfoPorts(t *testing.T) {
+	root := fakeSysfs(t)
+	writeFiles(t, portDir(root, "mlx5_0", "1"), map[string]string{
+		"state":                       "4: ACTIVE",
+		"phys_state":                  "5: LinkUp",
+		"rate":                        "100 Gb/sec (4X EDR)",
+		"link_layer":                  "InfiniBand",
+		"counters/port_xmit_data":     "1000",
+		"counters/port_rcv_data":      "2000",
+		"counters/port_xmit_packets":  "10",
+		"counters/port_rcv_packets":   "20",
+		"counters/port_rcv_errors":    "1",
+		"counters/port_xmit_discards": "2",
+		"counters/symbol_error":       "3",
+		"counters/link_downed":        "4",
+	})
+	writeFiles(t, portDir(root, "mlx5_1", "1"), map[string]string{
+		"state":                   "1: DOWN",
+		"phys_state":              "3: Disabled",
+		"rate":                    "25 Gb/sec (1X EDR)",
+		"link_layer":              "Ethernet",
+		"gid_attrs/ndevs/0":       "ens1f1",
+		"counters/port_xmit_data": "0",
+	})
+	writeFiles(t, portDir(root, "mlx5_0", "2"), map[string]string{
+		"state":      "2: INIT",
+		"phys_state": "5: LinkUp",
+		"rate":       "2.5 Gb/sec (1X SDR)",
+		"link_layer": "InfiniBand",
+	})
+	// 非数字的目录不是端口
+	writeFiles(t, portDir(root, "mlx5_0", "README"), map[string]string{"x": ""})
+
+	ports := GetRDMAInfo()
+	want := []models.RDMAPort{
+		{
+			Device: "mlx5_0", Port: 1, State: "ACTIVE", PhysState: "LinkUp",
+			Rate: "100 Gb/sec (4X EDR)", RateGbps: 100, LinkLayer: "InfiniBand",
+			XmitBytes: 4000, RcvBytes: 8000, XmitPackets: 10, RcvPackets: 20,
+			RcvErrors: 1, XmitDiscards: 2, SymbolErrors: 3, LinkDowned: 4,
+		},
+		{
+			Device: "mlx5_0", Port: 2, State: "INIT", PhysState: "LinkUp",
+			Rate: "2.5 Gb/sec (1X SDR)", RateGbps: 2.5, LinkLayer: "InfiniBand",
+		},
+		{
+			Device: "mlx5_1", Port: 1, State: "DOWN", PhysState: "Disabled",
+			Rate: "25 Gb/sec (1X EDR)", RateGbps: 25, LinkLayer: "Ethernet", NetDev: "ens1f1",
+		},
+	}
+	if len(ports) != len(want) {
+		t.Fatalf("GetRDMAInfo() returned %d ports, want %d: %+v", len(ports), len(want), ports)
+	}
+	for i := range want {
+		if ports[i] != want[i] {
+			t.Errorf("port %d = %+v, want %+v", i, ports[i], want[i])
+		}
+	}
+}
+
+func TestGetRDMAInfoRates(t *testing.T) {
+	root := fakeSysfs(t)
+	dir := portDir(root, "mlx5_0", "1")
+	counters := func(xmit, rcv string) {
+		writeFiles(t, dir, map[string]string{
+			"state":                   "4: ACTIVE",
+			"counters/port_xmit_data": xmit,
+			"counters/port_rcv_data":  rcv,
+		})
+	}
+
+	// 第一次采样没有速率
+	counters("1000", "1000")
+	first := GetRDMAInfo()
+	if first[0].SpeedUp != nil || first[0].SpeedDown != nil {
+		t.Fatalf("first sample has speeds: %+v", first[0])
+	}
+
+	// 2 秒内发送 2000×4 字节、接收 500×4 字节
+	counters("3000", "1500")
+	rewindRDMA(2 * time.Second)
+	second := GetRDMAInfo()[0]
+	assertSpeed(t, "speed_up", second.SpeedUp, 4000)
+	assertSpeed(t, "speed_down", second.SpeedDown, 1000)
+
+	// 发送计数复位（如驱动重载），该方向跳过一次，接收方向照常计算
+	counters("100", "2500")
+	rewindRDMA(2 * time.Second)
+	reset := GetRDMAInfo()[0]
+	if reset.SpeedUp != nil {
+		t.Errorf("speed_up after counter reset = %v, want nil", *reset.SpeedUp)
+	}
+	assertSpeed(t, "speed_down", reset.SpeedDown, 2000)
+
+	// 复位后以新的计数为基准
+	counters("600", "2500")
+	rewindRDMA(time.Second)
+	after := GetRDMAInfo()[0]
+	assertSpeed(t, "speed_up", after.SpeedUp, 2000)
+	assertSpeed(t, "speed_down", after.SpeedDown, 0)
+}
+
+// assertSpeed 速率允许 5% 的误差（采样间隔中包含测试本身的耗时）
+func assertSpeed(t *testing.T, name string, got *float64, want float64) {
+	t.Helper()
+	if got == nil {
+		t.Errorf("%s = nil, want %.0f", name, want)
+		return
+	}
+	if math.Abs(*got-want) > want*0.05 {
+		t.Errorf("%s = %.1f, want %.0f", name, *got, want)
+	}
+}
+
+func TestParseRDMARate(t *testing.T) {
+	tests := map[string]float64{
+		"100 Gb/sec (4X EDR)": 100,
+		"200 Gb/sec (4X HDR)": 200,
+		"2.5 Gb/sec (1X SDR)": 2.5,
+		"":                    0,
+		"invalid":             0,
+	}
+	for in, want := range tests {
+		if got := parseRDMARate(in); got != want {
+			t.Errorf("parseRDMARate(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestStripRDMAStateCode(t *testing.T) {
+	tests := map[string]string{
+		"4: ACTIVE": "ACTIVE",
+		"5: LinkUp": "LinkUp",
+		"ACTIVE":    "ACTIVE",
+		"":          "",
+	}
+	for in, want := range tests {
+		if got := stripRDMAStateCode(in); got != want {
+			t.Errorf("stripRDMAStateCode(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
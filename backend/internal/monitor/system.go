@@ -2,10 +2,9 @@ package monitor
 
 import (
 	"fmt"
-	"runtime"
+	"sync"
 	"time"
 
-	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/disk"
 	"github.com/shirou/gopsutil/v3/host"
 	"github.com/shirou/gopsutil/v3/mem"
@@ -16,6 +15,7 @@ import (
 
 // NetworkStats 存储上一次的网络统计信息
 var (
+	networkMu        sync.Mutex
 	lastNetworkStats = make(map[string]net.IOCountersStat)
 	lastNetworkTime  time.Time
 )
@@ -34,56 +34,33 @@ func FormatBytes(bytes uint64) string {
 	return fmt.Sprintf("%.2f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
 
-// GetCPUInfo 获取CPU信息
-func GetCPUInfo() models.CPUInfo {
-	// 获取CPU型号
-	brand, _ := cpu.Info()
-	var brandStr string
-	if len(brand) > 0 {
-		brandStr = brand[0].ModelName
-	}
-	if brandStr == "" {
-		brandStr = runtime.GOARCH + " CPU"
-	}
-
-	// 获取物理核心数和逻辑核心数
-	physicalCores, _ := cpu.Counts(false)
-	logicalCores, _ := cpu.Counts(true)
-
-	// 获取每核心占用率
-	perCorePercent, _ := cpu.Percent(0, true)
-
-	// 计算总体占用率
-	var totalPercent float64
-	if len(perCorePercent) > 0 {
-		sum := 0.0
-		for _, p := range perCorePercent {
-			sum += p
-		}
-		totalPercent = sum / float64(len(perCorePercent))
-	}
-
-	return models.CPUInfo{
-		Brand:          brandStr,
-		Percent:        totalPercent,
-		Cores:          physicalCores,
-		Threads:        logicalCores,
-		PerCorePercent: perCorePercent,
-	}
-}
-
 // GetMemoryInfo 获取内存信息
 func GetMemoryInfo() models.MemoryInfo {
 	vmem, _ := mem.VirtualMemory()
+	modules := GetMemoryModules()
 
 	return models.MemoryInfo{
-		Total:      vmem.Total,
-		Used:       vmem.Used,
-		Free:       vmem.Free,
-		Percent:    vmem.UsedPercent,
-		TotalHuman: FormatBytes(vmem.Total),
-		UsedHuman:  FormatBytes(vmem.Used),
-		FreeHuman:  FormatBytes(vmem.Free),
+		Total:          vmem.Total,
+		Used:           vmem.Used,
+		Free:           vmem.Free,
+		Percent:        vmem.UsedPercent,
+		TotalHuman:     FormatBytes(vmem.Total),
+		UsedHuman:      FormatBytes(vmem.Used),
+		FreeHuman:      FormatBytes(vmem.Free),
+		Available:      vmem.Available,
+		AvailableHuman: FormatBytes(vmem.Available),
+		Cached:         vmem.Cached,
+		Buffers:        vmem.Buffers,
+		Shared:         vmem.Shared,
+		Slab:           vmem.Slab,
+		Dirty:          vmem.Dirty,
+		WriteBack:      vmem.WriteBack,
+		Model:          memoryModelString(modules),
+		Swap:           GetSwapInfo(),
+		Shm:            GetShmInfo(),
+		HugePages:      GetHugePages(),
+		NUMA:           GetNUMAMemory(),
+		Modules:        modules,
 	}
 }
 
@@ -162,39 +139,41 @@ func GetDiskInfo() []models.DiskInfo {
 func GetNetworkInfo() []models.NetworkInterface {
 	var interfaces []models.NetworkInterface
 
+	networkMu.Lock()
+	defer networkMu.Unlock()
+
 	currentTime := time.Now()
 	netIO, _ := net.IOCounters(true)
 
+	// 获取网络接口地址
+	ifaceAddrs := make(map[string][]string)
+	if ifaces, err := net.Interfaces(); err == nil {
+		for _, iface := range ifaces {
+			for _, addr := range iface.Addrs {
+				if addr.Addr != "" {
+					ifaceAddrs[iface.Name] = append(ifaceAddrs[iface.Name], addr.Addr)
+				}
+			}
+		}
+	}
+
 	// 过滤物理网卡
 	for _, stats := range netIO {
-		// 跳过本地回环和虚拟接口
+		// 跳过本地回环
 		if stats.Name == "lo" || stats.Name == "Loopback" {
 			continue
 		}
 
-		// 获取网络接口地址
-		addrs, _ := net.Interfaces()
+		ipv4Addrs, ipv6Addrs := classifyAddrs(ifaceAddrs[stats.Name])
 		var ipv4, ipv6 string
-		var speed *int
-
-		for _, iface := range addrs {
-			if iface.Name == stats.Name {
-				for _, addr := range iface.Addrs {
-					if addr.Addr != "" {
-						if len(addr.Addr) < 20 { // 简单判断IPv4
-							if ipv4 == "" {
-								ipv4 = addr.Addr
-							}
-						} else {
-							if ipv6 == "" {
-								ipv6 = addr.Addr
-							}
-						}
-					}
-				}
-				break
-			}
+		if len(ipv4Addrs) > 0 {
+			ipv4 = ipv4Addrs[0]
 		}
+		if len(ipv6Addrs) > 0 {
+			ipv6 = ipv6Addrs[0]
+		}
+
+		details := readNICDetails(stats.Name)
 
 		var speedUp, speedDown *float64
 
@@ -217,9 +196,19 @@ func GetNetworkInfo() []models.NetworkInterface {
 
 		interfaces = append(interfaces, models.NetworkInterface{
 			Name:        stats.Name,
+			Model:       details.model,
+			Driver:      details.driver,
 			IPv4:        ipv4,
 			IPv6:        ipv6,
-			Speed:       speed,
+			IPv4Addrs:   ipv4Addrs,
+			IPv6Addrs:   ipv6Addrs,
+			Speed:       details.speed,
+			Duplex:      details.duplex,
+			MTU:         details.mtu,
+			OperState:   details.operState,
+			Virtual:     details.virtual,
+			Bridge:      details.bridge,
+			Master:      details.master,
 			BytesSent:   stats.BytesSent,
 			BytesRecv:   stats.BytesRecv,
 			PacketsSent: stats.PacketsSent,
@@ -238,7 +227,7 @@ func GetNetworkInfo() []models.NetworkInterface {
 
 	lastNetworkTime = currentTime
 
-	return interfaces
+	return applyNetworkOptions(interfaces)
 }
 
 // GetUptime 获取系统运行时间（秒）
@@ -274,7 +263,10 @@ func GetSystemInfo() models.SystemInfo {
 		Memory:   GetMemoryInfo(),
 		Disks:    GetDiskInfo(),
 		Uptime:   GetUptime(),
+		Load:     GetLoadInfo(),
+		Pressure: GetPressureInfo(),
 		GPU:      GetGPUInfo(),
 		Network:  GetNetworkInfo(),
+		RDMA:     GetRDMAInfo(),
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/dat-G/MLServer_Dash/backend/internal/cluster"
 	"github.com/dat-G/MLServer_Dash/backend/internal/config"
 	embedfs "github.com/dat-G/MLServer_Dash/backend/internal/embed"
 	"github.com/dat-G/MLServer_Dash/backend/internal/handlers"
@@ -35,10 +36,34 @@ func setupAPIRoutes(router *gin.Engine) {
 	api := router.Group("/api")
 	{
 		api.GET("/ws", ws.HandleWebSocket)
+		api.GET("/stream", ws.HandleStream)
+		api.GET("/ws/stats", ws.HandleStats)
 		api.GET("/system", handlers.SystemInfoHandler)
+		api.GET("/storage/dirs", handlers.StorageDirsHandler)
 		api.GET("/docker", handlers.DockerListHandler)
 		api.POST("/docker/:container_id/action", handlers.DockerActionHandler)
+		api.POST("/processes/:pid/kill", handlers.ProcessKillHandler)
 		api.GET("/health", handlers.HealthCheckHandler)
+		api.GET("/audit", handlers.AuditLogHandler)
+		api.GET("/config", handlers.ConfigHandler)
+		api.PUT("/config", handlers.UpdateConfigHandler)
+
+		api.GET("/nodes", handlers.NodesHandler)
+		api.GET("/nodes/:id", handlers.NodeHandler)
+		api.GET("/nodes/:id/system", handlers.NodeSystemHandler)
+		api.GET("/nodes/:id/docker", handlers.NodeDockerHandler)
+		api.GET("/nodes/:id/history", handlers.NodeHistoryHandler)
+		api.DELETE("/nodes/:id", handlers.NodeRemoveHandler)
+		api.GET("/cluster/gpus", handlers.ClusterGPUsHandler)
+		api.GET("/agent/ws", cluster.HandleAgent)
+
+		api.POST("/auth/login", handlers.LoginHandler)
+		api.POST("/auth/logout", handlers.LogoutHandler)
+		api.GET("/auth/me", handlers.MeHandler)
+		api.POST("/auth/refresh", handlers.RefreshHandler)
+		api.GET("/auth/providers", handlers.ProvidersHandler)
+		api.GET("/auth/oidc/login", handlers.OIDCLoginHandler)
+		api.GET("/auth/oidc/callback", handlers.OIDCCallbackHandler)
 	}
 }
 
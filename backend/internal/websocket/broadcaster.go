@@ -1,78 +1,121 @@
 package websocket
 
 import (
+	"context"
 	"log"
 	"time"
 
+	"github.com/dat-G/MLServer_Dash/backend/internal/cluster"
 	"github.com/dat-G/MLServer_Dash/backend/internal/config"
 	"github.com/dat-G/MLServer_Dash/backend/internal/docker"
 	"github.com/dat-G/MLServer_Dash/backend/internal/monitor"
 )
 
-// StartBroadcasters 启动广播器
-func StartBroadcasters(cfg *config.Config) {
-	// 从配置获取轮询间隔，默认 2000ms
-	interval := time.Duration(cfg.Server.PollInterval) * time.Millisecond
+// processTopN processes 主题推送的进程数量
+const processTopN = 30
 
-	// 启动系统信息广播器
-	go broadcastSystemInfo(interval)
-
-	// 启动 Docker 信息广播器
-	go broadcastDockerInfo(interval)
+// idleCheckInterval 没有订阅者时采集器的检查间隔
+const idleCheckInterval = 5 * time.Second
 
-	log.Printf("Broadcasters started with interval: %v", interval)
+// collectorWake 订阅或推送间隔变化时唤醒对应采集器，使其立即按新的间隔调度
+var collectorWake = map[string]chan struct{}{
+	TopicSystem:    make(chan struct{}, 1),
+	TopicGPU:       make(chan struct{}, 1),
+	TopicDocker:    make(chan struct{}, 1),
+	TopicProcesses: make(chan struct{}, 1),
 }
 
-// broadcastSystemInfo 定期广播系统信息
-func broadcastSystemInfo(interval time.Duration) {
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
-
-	// 立即发送一次
-	if HubInstance != nil && HubInstance.ClientCount() > 0 {
+// StartBroadcasters 启动广播器，ctx 取消后停止
+// 每个主题的采集频率取订阅者请求的最短间隔，没有订阅者时不采集
+func StartBroadcasters(ctx context.Context, cfg *config.Config) {
+	// 启动系统信息广播器
+	startCollector(ctx, TopicSystem, func() interface{} {
 		systemInfo := monitor.GetSystemInfo()
+		cluster.RecordLocal(systemInfo)
+		cluster.Annotate(&systemInfo)
 		systemInfo.WSClients = HubInstance.ClientCount()
-		HubInstance.BroadcastSystem(systemInfo)
-	}
+		return systemInfo
+	})
 
-	for range ticker.C {
-		if HubInstance == nil {
-			continue
-		}
+	// 启动 GPU 信息广播器
+	startCollector(ctx, TopicGPU, func() interface{} {
+		return monitor.GetGPUInfo()
+	})
 
-		if HubInstance.ClientCount() == 0 {
-			// 没有客户端连接，跳过数据收集
-			continue
-		}
+	// 启动 Docker 信息广播器
+	startCollector(ctx, TopicDocker, func() interface{} {
+		return docker.GetContainers()
+	})
 
-		systemInfo := monitor.GetSystemInfo()
-		systemInfo.WSClients = HubInstance.ClientCount()
-		HubInstance.BroadcastSystem(systemInfo)
-	}
+	// 启动进程列表广播器
+	startCollector(ctx, TopicProcesses, func() interface{} {
+		return monitor.GetProcesses(processTopN)
+	})
+
+	log.Printf("Broadcasters started with default interval: %v (clients may request %dms-%dms)",
+		currentSettings().defaultInterval, cfg.WebSocket.MinInterval, cfg.WebSocket.MaxInterval)
 }
 
-// broadcastDockerInfo 定期广播 Docker 容器信息
-func broadcastDockerInfo(interval time.Duration) {
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
+// startCollector 在后台运行主题的采集器
+func startCollector(ctx context.Context, topic string, collect func() interface{}) {
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		broadcastTopic(ctx, topic, collect)
+	}()
+}
 
-	// 立即发送一次
-	if HubInstance != nil && HubInstance.ClientCount() > 0 {
-		containers := docker.GetContainers()
-		HubInstance.BroadcastDocker(containers)
+// wakeCollector 唤醒主题对应的采集器
+func wakeCollector(topic string) {
+	if wake, ok := collectorWake[topic]; ok {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
 	}
+}
+
+// broadcastTopic 按订阅者需要的最快间隔采集并广播一个主题，直到 ctx 取消
+func broadcastTopic(ctx context.Context, topic string, collect func() interface{}) {
+	wake := collectorWake[topic]
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	var lastRun time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		case <-wake:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+		}
 
-	for range ticker.C {
 		if HubInstance == nil {
+			timer.Reset(idleCheckInterval)
+			continue
+		}
+
+		interval := HubInstance.TopicInterval(topic)
+		if interval == 0 {
+			// 没有客户端订阅，跳过数据收集
+			timer.Reset(idleCheckInterval)
 			continue
 		}
 
-		if HubInstance.ClientCount() == 0 {
-			// 没有客户端连接，跳过数据收集
+		// 被唤醒时如果距上次采集还不到新的间隔，等到期再采集
+		if wait := interval - time.Since(lastRun); !lastRun.IsZero() && wait > 0 {
+			timer.Reset(wait)
 			continue
 		}
 
-		containers := docker.GetContainers()
-		HubInstance.BroadcastDocker(containers)
+		lastRun = time.Now()
+		HubInstance.Publish(topic, collect())
+		timer.Reset(interval)
 	}
 }
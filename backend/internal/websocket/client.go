@@ -1,11 +1,14 @@
 package websocket
 
 import (
-	"encoding/json"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"golang.org/x/time/rate"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/auth"
 )
 
 const (
@@ -15,33 +18,69 @@ const (
 	pingPeriod = 54 * time.Second
 	// writeWait 写入消息的超时时间
 	writeWait = 10 * time.Second
+	// maxMessageSize 客户端消息的最大长度
+	maxMessageSize = 4096
 )
 
 // Client 表示 WebSocket 客户端连接
 type Client struct {
-	hub  *Hub
-	conn *websocket.Conn
-	send chan Message
+	hub   *Hub
+	conn  *websocket.Conn
+	send  *sendQueue
+	ip    string
+	codec *messageCodec // 按协商的子协议选择的编码
+
+	// principal 建立连接时认证的用户，未启用认证时为 nil
+	principal *auth.Principal
+
+	mu     sync.RWMutex
+	topics map[string]*subscription // 已订阅的主题
+
+	// hub 移除客户端时设置，写协程据此发送关闭帧
+	closeCode   int
+	closeReason string
+}
+
+// closeWith 记录关闭原因并关闭发送队列，调用方需持有 hub 的写锁，每个客户端只调用一次
+// 字段在关闭 done 之前写入，写协程在 done 关闭之后读取
+func (c *Client) closeWith(code int, reason string) {
+	c.closeCode, c.closeReason = code, reason
+	c.send.close()
 }
 
 // NewClient 创建一个新的客户端
-func NewClient(hub *Hub, conn *websocket.Conn) *Client {
-	return &Client{
-		hub:  hub,
-		conn: conn,
-		send: make(chan Message, 256),
+func NewClient(hub *Hub, conn *websocket.Conn, ip string, principal *auth.Principal) *Client {
+	c := &Client{
+		hub:       hub,
+		conn:      conn,
+		send:      newSendQueue(sendQueueSize),
+		ip:        ip,
+		codec:     codecFor(conn.Subprotocol()),
+		principal: principal,
+		topics:    make(map[string]*subscription),
+	}
+	for _, topic := range defaultTopics {
+		c.topics[topic] = &subscription{}
 	}
+	return c
 }
 
 // ReadPump 从 WebSocket 连接读取消息
-// 客户端可以发送 ping 消息保持连接
+// 客户端可以发送 ping 消息保持连接；超过消息速率限制时以 1008 关闭连接
 func (c *Client) ReadPump() {
 	defer func() {
-		c.hub.unregister <- c
+		c.hub.Unregister(c)
 		c.conn.Close()
+		limiter.Release(c.ip)
 	}()
 
-	c.conn.SetReadLimit(512)
+	var messages *rate.Limiter
+	if ws := currentSettings().ws; ws.MessageRate > 0 {
+		burst := max(ws.MessageBurst, 1)
+		messages = rate.NewLimiter(rate.Limit(ws.MessageRate), burst)
+	}
+
+	c.conn.SetReadLimit(maxMessageSize)
 	c.conn.SetReadDeadline(time.Now().Add(pongWait))
 	// 配置 pong 处理器
 	c.conn.SetPongHandler(func(string) error {
@@ -50,46 +89,78 @@ func (c *Client) ReadPump() {
 	})
 
 	for {
-		_, _, err := c.conn.ReadMessage()
+		frameType, data, err := c.conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Printf("WebSocket error: %v", err)
 			}
 			break
 		}
+
+		if messages != nil && !messages.Allow() {
+			log.Printf("WebSocket: closing connection from %s: %s", c.ip, reasonRateLimited)
+			c.conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reasonRateLimited),
+				time.Now().Add(writeWait))
+			break
+		}
+
+		c.handleRequest(frameType, data)
+	}
+}
+
+// write 按客户端协商的编码写入消息
+// 广播消息使用共享的编码缓存，只有增量补丁和控制消息单独序列化
+func (c *Client) write(message Message) error {
+	if message.frames != nil {
+		frame := message.frames.encode(message, c.codec)
+		if frame.err != nil {
+			log.Printf("Failed to marshal message: %v", frame.err)
+			return nil
+		}
+		return c.conn.WritePreparedMessage(frame.prepared)
+	}
+
+	data, err := c.codec.marshal(message)
+	if err != nil {
+		log.Printf("Failed to marshal message: %v", err)
+		return nil
 	}
+	return c.conn.WriteMessage(c.codec.frameType, data)
 }
 
 // WritePump 将消息写入 WebSocket 连接
+// 调用前需执行 hub.pumps.Add(1)，hub 关闭时据此等待关闭帧发出
 func (c *Client) WritePump() {
 	ticker := time.NewTicker(pingPeriod)
 	defer func() {
 		ticker.Stop()
 		c.conn.Close()
+		c.hub.pumps.Done()
 	}()
 
 	for {
 		select {
-		case message, ok := <-c.send:
-			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
-			if !ok {
-				// Hub 关闭了通道
-				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
-				return
-			}
+		case <-c.send.ready:
+			for _, message := range c.send.drain() {
+				// 增量模式的主题转换为快照或补丁
+				message, changed := c.encodeDelta(message)
+				if !changed {
+					continue
+				}
 
-			// 序列化消息
-			data, err := json.Marshal(message)
-			if err != nil {
-				log.Printf("Failed to marshal message: %v", err)
-				continue
+				c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+				if err := c.write(message); err != nil {
+					log.Printf("Failed to write message: %v", err)
+					return
+				}
 			}
 
-			err = c.conn.WriteMessage(websocket.TextMessage, data)
-			if err != nil {
-				log.Printf("Failed to write message: %v", err)
-				return
-			}
+		case <-c.send.done:
+			// Hub 移除了客户端
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(c.closeCode, c.closeReason))
+			return
 
 		case <-ticker.C:
 			// 发送 ping 消息保持连接
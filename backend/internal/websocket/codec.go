@@ -0,0 +1,117 @@
+package websocket
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/ugorji/go/codec"
+)
+
+// 通过 Sec-WebSocket-Protocol 协商的消息编码，未协商时使用 JSON 文本帧
+const (
+	ProtocolJSON    = "json"
+	ProtocolMsgpack = "msgpack"
+	ProtocolCBOR    = "cbor"
+)
+
+// subprotocols 服务器支持的子协议，按优先级排列
+var subprotocols = []string{ProtocolMsgpack, ProtocolCBOR, ProtocolJSON}
+
+// messageCodec 消息编码方式
+type messageCodec struct {
+	name      string
+	frameType int // websocket.TextMessage 或 websocket.BinaryMessage
+	marshal   func(v interface{}) ([]byte, error)
+	unmarshal func(data []byte, v interface{}) error
+}
+
+var (
+	// msgpackHandle 使用新版规范（str8 / bin 类型，时间编码为 timestamp 扩展）
+	msgpackHandle = &codec.MsgpackHandle{WriteExt: true}
+	cborHandle    = &codec.CborHandle{}
+)
+
+var (
+	jsonCodec = &messageCodec{
+		name:      ProtocolJSON,
+		frameType: websocket.TextMessage,
+		marshal:   json.Marshal,
+		unmarshal: json.Unmarshal,
+	}
+	msgpackCodec = &messageCodec{
+		name:      ProtocolMsgpack,
+		frameType: websocket.BinaryMessage,
+		marshal:   handleMarshal(msgpackHandle),
+		unmarshal: handleUnmarshal(msgpackHandle),
+	}
+	cborCodec = &messageCodec{
+		name:      ProtocolCBOR,
+		frameType: websocket.BinaryMessage,
+		marshal:   handleMarshal(cborHandle),
+		unmarshal: handleUnmarshal(cborHandle),
+	}
+)
+
+// codecFor 根据协商的子协议选择编码方式
+func codecFor(subprotocol string) *messageCodec {
+	switch subprotocol {
+	case ProtocolMsgpack:
+		return msgpackCodec
+	case ProtocolCBOR:
+		return cborCodec
+	default:
+		return jsonCodec
+	}
+}
+
+// handleMarshal 使用 ugorji codec 编码，字段名沿用 json 标签
+func handleMarshal(h codec.Handle) func(v interface{}) ([]byte, error) {
+	return func(v interface{}) ([]byte, error) {
+		var data []byte
+		err := codec.NewEncoderBytes(&data, h).Encode(v)
+		return data, err
+	}
+}
+
+// handleUnmarshal 使用 ugorji codec 解码
+func handleUnmarshal(h codec.Handle) func(data []byte, v interface{}) error {
+	return func(data []byte, v interface{}) error {
+		return codec.NewDecoderBytes(data, h).Decode(v)
+	}
+}
+
+// frameCache 广播消息的编码缓存，同一条消息在所有客户端之间共享
+// 每种编码只序列化一次；PreparedMessage 同时缓存压缩后的帧
+type frameCache struct {
+	mu     sync.Mutex
+	frames map[string]*encodedFrame
+}
+
+// encodedFrame 一种编码下的消息
+type encodedFrame struct {
+	data     []byte
+	prepared *websocket.PreparedMessage
+	err      error
+}
+
+func newFrameCache() *frameCache {
+	return &frameCache{frames: make(map[string]*encodedFrame)}
+}
+
+// encode 返回消息在 mc 编码下的帧，首次调用时序列化
+func (fc *frameCache) encode(msg Message, mc *messageCodec) *encodedFrame {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	if frame, ok := fc.frames[mc.name]; ok {
+		return frame
+	}
+	frame := &encodedFrame{}
+	frame.data, frame.err = mc.marshal(msg)
+	if frame.err == nil {
+		frame.prepared, frame.err = websocket.NewPreparedMessage(mc.frameType, frame.data)
+	}
+	fc.frames[mc.name] = frame
+	return frame
+}
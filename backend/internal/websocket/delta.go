@@ -0,0 +1,111 @@
+package websocket
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PatchOp JSON Patch (RFC 6902) 操作，只使用 add / remove / replace
+// value 不使用 omitempty，否则替换为 null 的操作会丢失值；remove 操作中的 value 会被忽略
+type PatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// toGeneric 将任意数据转换为 JSON 通用结构（map / slice / float64 / string / bool / nil）
+func toGeneric(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// diffJSON 计算从 old 到 new 的 JSON Patch
+// 对象逐字段比较；数组长度不变时逐元素比较，长度变化时整体替换
+func diffJSON(path string, old, new interface{}, ops []PatchOp) []PatchOp {
+	switch n := new.(type) {
+	case map[string]interface{}:
+		o, ok := old.(map[string]interface{})
+		if !ok {
+			return append(ops, PatchOp{Op: "replace", Path: path, Value: new})
+		}
+
+		keys := make([]string, 0, len(o)+len(n))
+		for k := range o {
+			keys = append(keys, k)
+		}
+		for k := range n {
+			if _, exists := o[k]; !exists {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			child := path + "/" + escapePointer(k)
+			ov, inOld := o[k]
+			nv, inNew := n[k]
+			switch {
+			case !inNew:
+				ops = append(ops, PatchOp{Op: "remove", Path: child})
+			case !inOld:
+				ops = append(ops, PatchOp{Op: "add", Path: child, Value: nv})
+			default:
+				ops = diffJSON(child, ov, nv, ops)
+			}
+		}
+		return ops
+
+	case []interface{}:
+		o, ok := old.([]interface{})
+		if !ok || len(o) != len(n) {
+			return append(ops, PatchOp{Op: "replace", Path: path, Value: new})
+		}
+		for i := range n {
+			ops = diffJSON(path+"/"+strconv.Itoa(i), o[i], n[i], ops)
+		}
+		return ops
+
+	default:
+		// 标量：类型或值不同则替换（old 为对象或数组时比较结果也为不同）
+		if !scalarEqual(old, new) {
+			ops = append(ops, PatchOp{Op: "replace", Path: path, Value: new})
+		}
+		return ops
+	}
+}
+
+// scalarEqual 比较两个 JSON 标量
+func scalarEqual(a, b interface{}) bool {
+	switch av := a.(type) {
+	case nil:
+		return b == nil
+	case float64:
+		bv, ok := b.(float64)
+		return ok && av == bv
+	case string:
+		bv, ok := b.(string)
+		return ok && av == bv
+	case bool:
+		bv, ok := b.(bool)
+		return ok && av == bv
+	default:
+		return false
+	}
+}
+
+// escapePointer 按 RFC 6901 转义 JSON Pointer 中的键
+func escapePointer(key string) string {
+	if !strings.ContainsAny(key, "~/") {
+		return key
+	}
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
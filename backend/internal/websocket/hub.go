@@ -1,28 +1,98 @@
 package websocket
 
 import (
+	"log"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
 )
 
 // Message 表示要广播的消息
+// 开启增量模式的主题先发送 snapshot，之后只发送 patch，seq 按主题逐条递增
 type Message struct {
-	Type string      `json:"type"` // "system" or "docker"
-	Data interface{} `json:"data"`
+	Type     string      `json:"type"` // 主题名称，或 subscribed / error 等控制消息
+	Data     interface{} `json:"data,omitempty"`
+	Seq      uint64      `json:"seq,omitempty"`
+	Snapshot bool        `json:"snapshot,omitempty"`
+	Patch    []PatchOp   `json:"patch,omitempty"`
+
+	resync bool        // 客户端请求重新同步，data 为上次发送的完整状态
+	frames *frameCache // 广播消息的编码缓存，为 nil 时由各客户端单独编码
+	id     uint64      // 广播消息的全局序号，用作 SSE 的事件 ID
+}
+
+// historySize 保留的最近广播消息数量，SSE 客户端重连时按 Last-Event-ID 回放
+const historySize = 256
+
+// slowClientLimit 连续丢弃这么多条消息仍未取出时，认为客户端已停止读取并断开
+const slowClientLimit = sendQueueSize
+
+// DropHook 消息被丢弃时的回调，evicted 表示客户端因持续跟不上而被断开
+// 回调在广播路径上同步执行（不持有 hub 的锁），不能阻塞，可能被并发调用
+type DropHook func(topic, ip string, evicted bool)
+
+// HubStats hub 的运行统计
+type HubStats struct {
+	Clients int    `json:"clients"`
+	Dropped uint64 `json:"dropped"` // 因发送队列已满丢弃的消息数
+	Evicted uint64 `json:"evicted"` // 因跟不上推送被断开的客户端数
+}
+
+// subscriberSet 一个主题的订阅者，list 是 members 的只读快照，成员变化时整体替换
+// 广播时在读锁内取出 list，释放锁之后再逐个写入发送队列
+type subscriberSet struct {
+	members map[*Client]struct{}
+	list    []*Client
+}
+
+func (s *subscriberSet) add(client *Client) {
+	if _, ok := s.members[client]; ok {
+		return
+	}
+	s.members[client] = struct{}{}
+	list := make([]*Client, len(s.list), len(s.list)+1)
+	copy(list, s.list)
+	s.list = append(list, client)
 }
 
-// Hub 维护活跃客户端集合并广播消息
+func (s *subscriberSet) remove(client *Client) {
+	if _, ok := s.members[client]; !ok {
+		return
+	}
+	delete(s.members, client)
+	list := make([]*Client, 0, len(s.members))
+	for _, c := range s.list {
+		if c != client {
+			list = append(list, c)
+		}
+	}
+	s.list = list
+}
+
+// Hub 维护活跃客户端集合，并按主题向订阅者分发消息
+// 广播在调用方 goroutine 中完成，只在读锁内取订阅者快照，写入各客户端的发送队列永不阻塞
 type Hub struct {
 	// 注册的客户端
-	clients map[*Client]bool
+	clients map[*Client]struct{}
+
+	// 主题 -> 订阅者，由 syncTopics 按客户端的订阅维护
+	topics map[string]*subscriberSet
 
-	// 注册客户端的请求
-	register chan *Client
+	// 最近的广播消息，按 id 递增，由 historyMu 保护
+	historyMu sync.Mutex
+	history   []Message
+	lastID    uint64
 
-	// 注销客户端的请求
-	unregister chan *Client
+	dropped  atomic.Uint64
+	evicted  atomic.Uint64
+	dropHook DropHook
 
-	// 广播消息
-	broadcast chan Message
+	// 关闭后新注册的客户端立即断开
+	closed bool
+	// 正在运行的写协程，关闭时等待它们发送关闭帧
+	pumps sync.WaitGroup
 
 	mu sync.RWMutex
 }
@@ -30,57 +100,238 @@ type Hub struct {
 // NewHub 创建一个新的 Hub 实例
 func NewHub() *Hub {
 	return &Hub{
-		clients:    make(map[*Client]bool),
-		register:   make(chan *Client),
-		unregister: make(chan *Client),
-		broadcast:  make(chan Message),
-	}
-}
-
-// Run 启动 hub 的主循环
-func (h *Hub) Run() {
-	for {
-		select {
-		case client := <-h.register:
-			h.mu.Lock()
-			h.clients[client] = true
-			h.mu.Unlock()
-		case client := <-h.unregister:
-			h.mu.Lock()
-			if _, ok := h.clients[client]; ok {
-				delete(h.clients, client)
-				close(client.send)
-			}
-			h.mu.Unlock()
-		case message := <-h.broadcast:
-			h.mu.RLock()
-			for client := range h.clients {
-				select {
-				case client.send <- message:
-				default:
-					// 客户端缓冲区已满，关闭连接
-					delete(h.clients, client)
-					close(client.send)
-				}
+		clients: make(map[*Client]struct{}),
+		topics:  make(map[string]*subscriberSet),
+	}
+}
+
+// Register 注册客户端并按其当前订阅加入各主题，hub 已关闭时立即断开客户端
+func (h *Hub) Register(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		client.closeWith(websocket.CloseGoingAway, reasonShuttingDown)
+		return
+	}
+	h.clients[client] = struct{}{}
+	h.syncTopicsLocked(client)
+}
+
+// Unregister 注销客户端并关闭其发送队列，客户端已被移除时不做任何操作
+func (h *Hub) Unregister(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeLocked(client, websocket.CloseNormalClosure, "")
+}
+
+// Close 断开所有客户端并拒绝新的注册，WebSocket 客户端收到 1001 关闭帧，可重复调用
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for client := range h.clients {
+		h.removeLocked(client, websocket.CloseGoingAway, reasonShuttingDown)
+	}
+}
+
+// Disconnect 以指定关闭码断开所有客户端，之后仍接受新的连接
+func (h *Hub) Disconnect(code int, reason string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for client := range h.clients {
+		h.removeLocked(client, code, reason)
+	}
+}
+
+// removeLocked 从客户端集合和所有主题中移除客户端并以指定关闭码断开，调用方需持有写锁
+func (h *Hub) removeLocked(client *Client, code int, reason string) bool {
+	if _, ok := h.clients[client]; !ok {
+		return false
+	}
+	delete(h.clients, client)
+	for topic, subscribers := range h.topics {
+		subscribers.remove(client)
+		if len(subscribers.members) == 0 {
+			delete(h.topics, topic)
+		}
+	}
+	client.closeWith(code, reason)
+	return true
+}
+
+// evict 断开持续跟不上推送的客户端，已被移除时返回 false，并发调用时只有一次生效
+func (h *Hub) evict(client *Client, streak int) bool {
+	h.mu.Lock()
+	removed := h.removeLocked(client, websocket.CloseTryAgainLater, reasonTooSlow)
+	h.mu.Unlock()
+	if removed {
+		h.evicted.Add(1)
+		log.Printf("WebSocket: evicting slow client %s after %d dropped messages", client.ip, streak)
+	}
+	return removed
+}
+
+// syncTopics 客户端修改订阅后更新主题索引
+func (h *Hub) syncTopics(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.clients[client]; ok {
+		h.syncTopicsLocked(client)
+	}
+}
+
+// syncTopicsLocked 调用方需持有写锁；锁顺序始终为先 hub 后客户端
+func (h *Hub) syncTopicsLocked(client *Client) {
+	subscribed := client.subscribedTopics()
+	for topic, subscribers := range h.topics {
+		if _, ok := subscribed[topic]; !ok {
+			subscribers.remove(client)
+			if len(subscribers.members) == 0 {
+				delete(h.topics, topic)
 			}
-			h.mu.RUnlock()
 		}
 	}
+	for topic := range subscribed {
+		subscribers, ok := h.topics[topic]
+		if !ok {
+			subscribers = &subscriberSet{members: make(map[*Client]struct{})}
+			h.topics[topic] = subscribers
+		}
+		subscribers.add(client)
+	}
+}
+
+// SetDropHook 设置消息丢弃回调，用于接入监控指标
+func (h *Hub) SetDropHook(hook DropHook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.dropHook = hook
+}
+
+// Stats 返回 hub 的运行统计
+func (h *Hub) Stats() HubStats {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return HubStats{
+		Clients: len(h.clients),
+		Dropped: h.dropped.Load(),
+		Evicted: h.evicted.Load(),
+	}
+}
+
+// record 为广播消息分配序号并保存到历史记录，调用方需持有 mu 的读锁
+// 记录与取订阅者快照在同一次读锁内完成，注册（写锁）时刻之前的消息都能在历史记录中找到
+func (h *Hub) record(message *Message) {
+	h.historyMu.Lock()
+	defer h.historyMu.Unlock()
+	h.lastID++
+	message.id = h.lastID
+	if len(h.history) < historySize {
+		h.history = append(h.history, *message)
+		return
+	}
+	copy(h.history, h.history[1:])
+	h.history[len(h.history)-1] = *message
+}
+
+// replay 返回序号大于 afterID 的历史广播消息
+func (h *Hub) replay(afterID uint64) []Message {
+	h.historyMu.Lock()
+	defer h.historyMu.Unlock()
+
+	var messages []Message
+	for _, message := range h.history {
+		if message.id > afterID {
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
+
+// Publish 向订阅了 topic 的客户端广播消息，不会阻塞调用方
+func (h *Hub) Publish(topic string, data interface{}) {
+	message := Message{
+		Type:   topic,
+		Data:   data,
+		frames: newFrameCache(),
+	}
+
+	h.mu.RLock()
+	h.record(&message)
+	var subscribers []*Client
+	if set, ok := h.topics[topic]; ok {
+		subscribers = set.list
+	}
+	hook := h.dropHook
+	h.mu.RUnlock()
+
+	// 快照之后才注销的客户端，其发送队列已关闭，写入会被忽略
+	now := time.Now()
+	for _, client := range subscribers {
+		if !client.shouldDeliver(topic, now) {
+			continue
+		}
+		h.enqueue(client, message, hook)
+	}
+}
+
+// enqueue 写入客户端的发送队列，持续跟不上的客户端被断开，调用方不能持有 hub 的锁
+func (h *Hub) enqueue(client *Client, message Message, hook DropHook) {
+	dropped, streak := client.send.push(message)
+	if !dropped {
+		return
+	}
+
+	h.dropped.Add(1)
+	evicted := streak >= slowClientLimit && h.evict(client, streak)
+	if hook != nil {
+		hook(message.Type, client.ip, evicted)
+	}
 }
 
 // BroadcastSystem 广播系统信息
 func (h *Hub) BroadcastSystem(data interface{}) {
-	h.broadcast <- Message{
-		Type: "system",
-		Data: data,
-	}
+	h.Publish(TopicSystem, data)
 }
 
 // BroadcastDocker 广播 Docker 容器信息
 func (h *Hub) BroadcastDocker(data interface{}) {
-	h.broadcast <- Message{
-		Type: "docker",
-		Data: data,
+	h.Publish(TopicDocker, data)
+}
+
+// HasSubscribers 是否有客户端订阅了 topic，用于跳过无人订阅的数据采集
+func (h *Hub) HasSubscribers(topic string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	_, ok := h.topics[topic]
+	return ok
+}
+
+// TopicInterval 订阅者中最短的推送间隔，即采集器需要的采集间隔；没有订阅者时返回 0
+func (h *Hub) TopicInterval(topic string) time.Duration {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	set, ok := h.topics[topic]
+	if !ok {
+		return 0
+	}
+	var fastest time.Duration
+	for _, client := range set.list {
+		if interval := client.interval(topic); interval > 0 && (fastest == 0 || interval < fastest) {
+			fastest = interval
+		}
+	}
+	return fastest
+}
+
+// sendTo 向单个客户端发送控制消息，客户端已断开时丢弃
+func (h *Hub) sendTo(client *Client, message Message) {
+	h.mu.RLock()
+	_, ok := h.clients[client]
+	hook := h.dropHook
+	h.mu.RUnlock()
+	if ok {
+		h.enqueue(client, message, hook)
 	}
 }
 
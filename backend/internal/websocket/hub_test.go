@@ -0,0 +1,270 @@
+package websocket
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// newTestClient 创建不带连接的客户端（与 SSE 客户端相同），订阅给定主题
+func newTestClient(hub *Hub, ip string, topics ...string) *Client {
+	return newStreamClient(hub, ip, nil, topics, 0)
+}
+
+// drainUntilClosed 持续取出客户端的消息直到队列关闭，返回取出的消息数
+func drainUntilClosed(c *Client) int {
+	n := 0
+	for {
+		select {
+		case <-c.send.ready:
+			n += len(c.send.drain())
+		case <-c.send.done:
+			return n
+		}
+	}
+}
+
+func isClosed(c *Client) bool {
+	select {
+	case <-c.send.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestHubConcurrentClients(t *testing.T) {
+	hub := NewHub()
+	var hookCalls atomic.Int64
+	hub.SetDropHook(func(topic, ip string, evicted bool) { hookCalls.Add(1) })
+
+	const clients = 300
+	topics := []string{"logs:a", "logs:b", "logs:c"}
+
+	var readers, workers, registered sync.WaitGroup
+	registered.Add(clients)
+	stop := make(chan struct{})
+
+	// 发布者持续向各主题广播
+	for _, topic := range topics {
+		workers.Add(1)
+		go func(topic string) {
+			defer workers.Done()
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				hub.Publish(topic, i)
+			}
+		}(topic)
+	}
+
+	// 客户端并发注册、修改订阅、读取并注销；一部分客户端保持连接直到 Close
+	all := make([]*Client, clients)
+	for i := range all {
+		all[i] = newTestClient(hub, fmt.Sprintf("10.0.%d.%d", i/256, i%256), topics[i%len(topics)])
+	}
+	for i, client := range all {
+		readers.Add(1)
+		go func(c *Client) {
+			defer readers.Done()
+			drainUntilClosed(c)
+		}(client)
+
+		workers.Add(1)
+		go func(i int, c *Client) {
+			defer workers.Done()
+			hub.Register(c)
+
+			c.mu.Lock()
+			c.topics[topics[(i+1)%len(topics)]] = &subscription{}
+			c.mu.Unlock()
+			hub.syncTopics(c)
+			hub.sendTo(c, Message{Type: "subscribed", Data: c.status()})
+			registered.Done()
+
+			_ = hub.HasSubscribers(topics[i%len(topics)])
+			_ = hub.TopicInterval(topics[i%len(topics)])
+			_ = hub.Stats()
+			_ = hub.replay(0)
+
+			if i%2 == 0 {
+				hub.Unregister(c)
+				hub.Unregister(c)
+			}
+		}(i, client)
+	}
+
+	// 所有客户端注册后，关闭与注销、发布并发进行
+	for i := 0; i < 4; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			registered.Wait()
+			hub.Close()
+		}()
+	}
+
+	// 发布者在所有客户端注册后才停止，保证发布与注册、订阅变更和关闭重叠
+	registered.Wait()
+	close(stop)
+	workers.Wait()
+	hub.Close()
+	readers.Wait()
+
+	if stats := hub.Stats(); stats.Clients != 0 {
+		t.Errorf("Stats().Clients after Close = %d, want 0", stats.Clients)
+	}
+	for _, topic := range topics {
+		if hub.HasSubscribers(topic) {
+			t.Errorf("HasSubscribers(%q) after Close = true", topic)
+		}
+	}
+	if dropped := hub.Stats().Dropped; uint64(hookCalls.Load()) != dropped {
+		t.Errorf("drop hook called %d times, Stats().Dropped = %d", hookCalls.Load(), dropped)
+	}
+
+	// 关闭后注册的客户端立即断开
+	late := newTestClient(hub, "10.1.0.1", topics[0])
+	hub.Register(late)
+	if !isClosed(late) || late.closeCode != websocket.CloseGoingAway {
+		t.Errorf("client registered after Close: closed=%v code=%d", isClosed(late), late.closeCode)
+	}
+}
+
+func TestHubDropOldest(t *testing.T) {
+	hub := NewHub()
+	type drop struct {
+		topic, ip string
+		evicted   bool
+	}
+	var drops []drop
+	hub.SetDropHook(func(topic, ip string, evicted bool) {
+		drops = append(drops, drop{topic, ip, evicted})
+	})
+
+	client := newTestClient(hub, "10.0.0.1", "logs:a")
+	hub.Register(client)
+
+	const extra = 10
+	for i := 0; i < sendQueueSize+extra; i++ {
+		hub.Publish("logs:a", i)
+	}
+
+	// 队列中保留最新的 sendQueueSize 条，按发布顺序排列
+	messages := client.send.drain()
+	if len(messages) != sendQueueSize {
+		t.Fatalf("queue holds %d messages, want %d", len(messages), sendQueueSize)
+	}
+	for i, message := range messages {
+		if want := extra + i; message.Data != want {
+			t.Fatalf("message %d = %v, want %d", i, message.Data, want)
+		}
+	}
+
+	if stats := hub.Stats(); stats.Dropped != extra || stats.Evicted != 0 || stats.Clients != 1 {
+		t.Errorf("Stats() = %+v, want dropped=%d evicted=0 clients=1", stats, extra)
+	}
+	if len(drops) != extra {
+		t.Fatalf("drop hook called %d times, want %d", len(drops), extra)
+	}
+	for _, d := range drops {
+		if d != (drop{"logs:a", "10.0.0.1", false}) {
+			t.Errorf("drop hook got %+v", d)
+		}
+	}
+
+	// 取出消息后连续丢弃计数清零，不会因此前的丢弃被断开
+	for i := 0; i < sendQueueSize+slowClientLimit-1; i++ {
+		hub.Publish("logs:a", i)
+	}
+	if isClosed(client) {
+		t.Error("client evicted before reaching slowClientLimit consecutive drops")
+	}
+}
+
+func TestHubEvictIdempotent(t *testing.T) {
+	hub := NewHub()
+	var evictions, hookCalls atomic.Int64
+	hub.SetDropHook(func(topic, ip string, evicted bool) {
+		hookCalls.Add(1)
+		if evicted {
+			evictions.Add(1)
+		}
+	})
+
+	slow := newTestClient(hub, "10.0.0.1", "logs:a")
+	hub.Register(slow)
+
+	// 多个发布者同时把慢速客户端推过断开阈值，只能断开一次
+	var wg sync.WaitGroup
+	for p := 0; p < 8; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < sendQueueSize+slowClientLimit; i++ {
+				hub.Publish("logs:a", i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if !isClosed(slow) {
+		t.Fatal("slow client was not evicted")
+	}
+	if slow.closeCode != websocket.CloseTryAgainLater || slow.closeReason != reasonTooSlow {
+		t.Errorf("slow client closed with %d %q", slow.closeCode, slow.closeReason)
+	}
+	if got := evictions.Load(); got != 1 {
+		t.Errorf("drop hook reported %d evictions, want 1", got)
+	}
+
+	// 重复断开、注销不改变统计
+	if hub.evict(slow, slowClientLimit) {
+		t.Error("evict() on an evicted client = true")
+	}
+	hub.Unregister(slow)
+	hub.sendTo(slow, Message{Type: "error"})
+
+	stats := hub.Stats()
+	if stats.Evicted != 1 || stats.Clients != 0 {
+		t.Errorf("Stats() = %+v, want evicted=1 clients=0", stats)
+	}
+	if hub.HasSubscribers("logs:a") {
+		t.Error("evicted client still subscribed")
+	}
+	if uint64(hookCalls.Load()) != stats.Dropped {
+		t.Errorf("drop hook called %d times, Stats().Dropped = %d", hookCalls.Load(), stats.Dropped)
+	}
+}
+
+func TestDropCounter(t *testing.T) {
+	hub := NewHub()
+	counter := newDropCounter()
+	hub.SetDropHook(counter.record)
+
+	client := newTestClient(hub, "10.0.0.1", "logs:a", "logs:b")
+	hub.Register(client)
+	for i := 0; i < sendQueueSize+3; i++ {
+		hub.Publish("logs:a", i)
+	}
+	for i := 0; i < slowClientLimit; i++ {
+		hub.Publish("logs:b", i)
+	}
+
+	// 日志主题合并为 logs 统计
+	topics := counter.snapshot()
+	if want := (TopicDrops{Dropped: slowClientLimit, Evicted: 1}); len(topics) != 1 || topics["logs"] != want {
+		t.Errorf("snapshot() = %+v, want only logs = %+v", topics, want)
+	}
+
+	counter.record(TopicSystem, "10.0.0.2", false)
+	if topics := counter.snapshot(); topics[TopicSystem] != (TopicDrops{Dropped: 1}) {
+		t.Errorf("system = %+v, want dropped=1", topics[TopicSystem])
+	}
+}
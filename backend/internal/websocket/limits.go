@@ -0,0 +1,133 @@
+package websocket
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/config"
+)
+
+// 连接被拒绝或关闭时的原因，随关闭帧发送给客户端
+const (
+	reasonTooManyConns = "too many connections"
+	reasonTooManyPerIP = "too many connections from this address"
+	reasonRateLimited  = "message rate limit exceeded"
+	reasonTooSlow      = "client too slow"
+	reasonShuttingDown = "server shutting down"
+	reasonAuthRequired = "authentication required"
+)
+
+// originChecker 按 server.corsOrigins 校验 WebSocket 握手的 Origin
+// "*" 只对 REST 的 CORS 生效：浏览器会为跨站的 WebSocket 握手附带 cookie，
+// 允许任意来源会让任何网页都能以当前用户的身份订阅数据，因此 "*" 在这里按同源处理
+type originChecker struct {
+	exact    map[string]bool
+	suffixes []string // 形如 https://*.example.com 的通配项，保存 scheme 和 ".example.com"
+}
+
+// newOriginChecker 解析允许的来源列表
+func newOriginChecker(origins []string) *originChecker {
+	oc := &originChecker{exact: make(map[string]bool)}
+	for _, origin := range origins {
+		origin = strings.ToLower(strings.TrimRight(strings.TrimSpace(origin), "/"))
+		switch {
+		case origin == "*":
+			// 按同源处理，见 originChecker
+		case strings.Contains(origin, "://*."):
+			oc.suffixes = append(oc.suffixes, strings.Replace(origin, "://*.", "://.", 1))
+		case origin != "":
+			oc.exact[origin] = true
+		}
+	}
+	return oc
+}
+
+// Check 实现 websocket.Upgrader.CheckOrigin
+// 没有 Origin 头的请求（脚本、命令行工具）和同源请求始终允许
+func (oc *originChecker) Check(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		log.Printf("WebSocket: rejected malformed origin %q", origin)
+		return false
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+
+	normalized := strings.ToLower(u.Scheme + "://" + u.Host)
+	if oc.exact[normalized] {
+		return true
+	}
+	for _, suffix := range oc.suffixes {
+		scheme, domain, _ := strings.Cut(suffix, "://")
+		if strings.EqualFold(u.Scheme, scheme) && strings.HasSuffix(strings.ToLower(u.Host), domain) {
+			return true
+		}
+	}
+
+	log.Printf("WebSocket: rejected connection from origin %s", origin)
+	return false
+}
+
+// connLimiter 统计总连接数和每个 IP 的连接数
+type connLimiter struct {
+	mu       sync.Mutex
+	maxTotal int
+	maxPerIP int
+	total    int
+	perIP    map[string]int
+}
+
+// newConnLimiter 创建连接计数器，上限为 0 表示不限制
+func newConnLimiter(cfg config.WebSocketConfig) *connLimiter {
+	return &connLimiter{
+		maxTotal: cfg.MaxConnections,
+		maxPerIP: cfg.MaxConnectionsPerIP,
+		perIP:    make(map[string]int),
+	}
+}
+
+// SetLimits 更新连接上限，已建立的连接不受影响
+func (l *connLimiter) SetLimits(cfg config.WebSocketConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxTotal = cfg.MaxConnections
+	l.maxPerIP = cfg.MaxConnectionsPerIP
+}
+
+// Acquire 占用一个连接名额，超限时返回关闭原因
+func (l *connLimiter) Acquire(ip string) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.maxTotal > 0 && l.total >= l.maxTotal {
+		return reasonTooManyConns, false
+	}
+	if l.maxPerIP > 0 && l.perIP[ip] >= l.maxPerIP {
+		return reasonTooManyPerIP, false
+	}
+	l.total++
+	l.perIP[ip]++
+	return "", true
+}
+
+// Release 释放连接名额
+func (l *connLimiter) Release(ip string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.total--
+	if l.perIP[ip] <= 1 {
+		delete(l.perIP, ip)
+	} else {
+		l.perIP[ip]--
+	}
+}
@@ -0,0 +1,36 @@
+package websocket
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOriginChecker(t *testing.T) {
+	tests := []struct {
+		name    string
+		origins []string
+		origin  string
+		want    bool
+	}{
+		{"no origin", []string{"*"}, "", true},
+		{"same host", []string{"*"}, "https://dash.example.com", true},
+		// "*" 只对 REST 的 CORS 生效，跨站页面不能建立 WebSocket
+		{"wildcard is same-origin", []string{"*"}, "https://evil.example", false},
+		{"exact", []string{"https://ui.example.org/"}, "https://UI.example.org", true},
+		{"exact mismatch", []string{"https://ui.example.org"}, "http://ui.example.org", false},
+		{"subdomain", []string{"https://*.example.org"}, "https://a.b.example.org", true},
+		{"subdomain excludes apex", []string{"https://*.example.org"}, "https://example.org", false},
+		{"malformed", []string{"*"}, "://", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "http://dash.example.com/ws", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if got := newOriginChecker(tt.origins).Check(r); got != tt.want {
+				t.Errorf("Check(%q) with %v = %v, want %v", tt.origin, tt.origins, got, tt.want)
+			}
+		})
+	}
+}
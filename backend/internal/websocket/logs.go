@@ -0,0 +1,102 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/auth"
+	"github.com/dat-G/MLServer_Dash/backend/internal/docker"
+	"github.com/dat-G/MLServer_Dash/backend/internal/models"
+)
+
+const (
+	// logTail 开始跟踪时回放的历史行数
+	logTail = 100
+	// logIdleCheck 检查日志主题是否还有订阅者的间隔
+	logIdleCheck = 5 * time.Second
+)
+
+// 正在跟踪的容器日志，键为容器 ID 或名称
+var (
+	logStreamsMu sync.Mutex
+	logStreams   = make(map[string]context.CancelFunc)
+)
+
+// authorizeLogs 检查调用方能否查看容器日志：需要 operator 角色，并满足适用于该用户的容器策略
+func authorizeLogs(p *auth.Principal, container string) error {
+	if err := auth.AuthorizeAction(p, auth.ActionLogs); err != nil {
+		return err
+	}
+	if p == nil {
+		return nil
+	}
+	name, labels, err := docker.InspectContainer(container)
+	if err != nil {
+		return fmt.Errorf("Container %s not found", container)
+	}
+	target := auth.ContainerTarget{ID: container, Name: name, Labels: labels}
+	return auth.ManagerInstance.AuthorizeContainer(p, auth.ActionLogs, target)
+}
+
+// startLogStream 开始跟踪容器日志并发布到 logs:<container>，已在跟踪时不重复启动
+// 没有订阅者、容器停止或应用关闭后自动结束
+func startLogStream(hub *Hub, container string) {
+	logStreamsMu.Lock()
+	defer logStreamsMu.Unlock()
+
+	if _, ok := logStreams[container]; ok || rootCtx.Err() != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(rootCtx)
+	logStreams[container] = cancel
+
+	topic := logsTopicPrefix + container
+	workers.Add(2)
+	go func() {
+		defer workers.Done()
+		watchLogSubscribers(ctx, cancel, hub, topic)
+	}()
+	go func() {
+		defer workers.Done()
+		defer func() {
+			cancel()
+			logStreamsMu.Lock()
+			delete(logStreams, container)
+			logStreamsMu.Unlock()
+		}()
+
+		err := docker.FollowLogs(ctx, container, logTail, func(stream, line string) {
+			hub.Publish(topic, models.ContainerLogLine{
+				Container: container,
+				Stream:    stream,
+				Line:      line,
+				Time:      time.Now(),
+			})
+		})
+		if err != nil && ctx.Err() == nil {
+			log.Printf("WebSocket: log stream for %s ended: %v", container, err)
+			hub.Publish(topic, errorStatus{Message: err.Error(), Topic: topic})
+		}
+	}()
+}
+
+// watchLogSubscribers 日志主题没有订阅者后停止跟踪
+func watchLogSubscribers(ctx context.Context, cancel context.CancelFunc, hub *Hub, topic string) {
+	ticker := time.NewTicker(logIdleCheck)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !hub.HasSubscribers(topic) {
+				cancel()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package websocket
+
+import "sync"
+
+// sendQueueSize 每个客户端发送队列的容量
+const sendQueueSize = 256
+
+// sendQueue 客户端的发送队列，写入永不阻塞
+// 队列已满时丢弃最旧的消息：周期性主题的新数据会覆盖旧数据，增量编码在写出时才进行，丢弃不会破坏补丁序列
+type sendQueue struct {
+	mu     sync.Mutex
+	items  []Message
+	limit  int
+	streak int // 上次取出之后连续丢弃的消息数
+	closed bool
+
+	ready chan struct{} // 有新消息时发出通知，容量为 1
+	done  chan struct{} // 队列关闭后关闭
+}
+
+func newSendQueue(limit int) *sendQueue {
+	return &sendQueue{
+		limit: limit,
+		ready: make(chan struct{}, 1),
+		done:  make(chan struct{}),
+	}
+}
+
+// push 加入一条消息，返回是否因队列已满丢弃了最旧的消息，以及连续丢弃的数量
+func (q *sendQueue) push(message Message) (dropped bool, streak int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return false, 0
+	}
+	if len(q.items) >= q.limit {
+		copy(q.items, q.items[1:])
+		q.items = q.items[:len(q.items)-1]
+		q.streak++
+		dropped = true
+	}
+	q.items = append(q.items, message)
+
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+	return dropped, q.streak
+}
+
+// drain 取出队列中的全部消息
+func (q *sendQueue) drain() []Message {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	items := q.items
+	q.items = nil
+	q.streak = 0
+	return items
+}
+
+// close 关闭队列并丢弃未发送的消息，可重复调用
+func (q *sendQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return
+	}
+	q.closed = true
+	q.items = nil
+	close(q.done)
+}
@@ -1,33 +1,93 @@
 package websocket
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/config"
+	"github.com/dat-G/MLServer_Dash/backend/internal/middleware"
 )
 
 // Hub 全局 hub 实例，由 main.go 初始化
 var HubInstance *Hub
 
-// upgrader 用于将 HTTP 连接升级为 WebSocket
+// upgrader 用于将 HTTP 连接升级为 WebSocket，Origin 按当前配置校验，压缩在 InitHub 中设置
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
+	Subprotocols:    subprotocols,
 	CheckOrigin: func(r *http.Request) bool {
-		// 允许所有来源，生产环境应该检查 Origin
-		return true
+		return currentSettings().origins.Check(r)
 	},
 }
 
-// InitHub 初始化 WebSocket hub
-func InitHub() {
+// limiter 连接数限制
+var limiter *connLimiter
+
+// settings 可以热加载的 WebSocket 设置，整体替换，读取时无需加锁
+type settings struct {
+	ws              config.WebSocketConfig
+	defaultInterval time.Duration // 客户端未指定间隔时的推送间隔（server.pollInterval）
+	origins         *originChecker
+}
+
+var current atomic.Pointer[settings]
+
+// currentSettings 返回当前设置，InitHub 之前返回默认值
+func currentSettings() *settings {
+	if s := current.Load(); s != nil {
+		return s
+	}
+	return &settings{defaultInterval: 2 * time.Second, origins: newOriginChecker([]string{"*"})}
+}
+
+// rootCtx 应用的根 context，取消后日志跟踪等后台任务随之结束
+var rootCtx = context.Background()
+
+// workers 采集器和日志跟踪等后台 goroutine，关闭时等待它们退出
+var workers sync.WaitGroup
+
+// InitHub 初始化 WebSocket hub，ctx 取消后后台任务停止
+func InitHub(ctx context.Context, cfg *config.Config) {
+	rootCtx = ctx
+	upgrader.EnableCompression = cfg.WebSocket.Compression
+	limiter = newConnLimiter(cfg.WebSocket)
+	ApplyConfig(cfg)
+
 	HubInstance = NewHub()
-	go HubInstance.Run()
+	HubInstance.SetDropHook(drops.record)
 	log.Println("WebSocket Hub initialized")
 }
 
+// ApplyConfig 应用新的 Origin 白名单、连接限制和推送间隔，配置热加载时调用
+// 已建立的连接保留原有的消息速率限制
+func ApplyConfig(cfg *config.Config) {
+	s := &settings{
+		ws:              cfg.WebSocket,
+		defaultInterval: 2 * time.Second,
+		origins:         newOriginChecker(cfg.Server.CORSOrigins),
+	}
+	if cfg.Server.PollInterval > 0 {
+		s.defaultInterval = time.Duration(cfg.Server.PollInterval) * time.Millisecond
+	}
+	current.Store(s)
+
+	if limiter != nil {
+		limiter.SetLimits(cfg.WebSocket)
+	}
+	// 推送间隔可能变化，唤醒采集器按新的间隔调度
+	for topic := range collectorWake {
+		wakeCollector(topic)
+	}
+}
+
 // HandleWebSocket 处理 WebSocket 连接请求
 func HandleWebSocket(c *gin.Context) {
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
@@ -42,12 +102,66 @@ func HandleWebSocket(c *gin.Context) {
 		return
 	}
 
-	client := NewClient(HubInstance, conn)
+	// 连接数超限时完成握手后立即发送关闭帧，浏览器可以读到关闭码和原因
+	ip := c.ClientIP()
+	if reason, ok := limiter.Acquire(ip); !ok {
+		log.Printf("WebSocket: rejected connection from %s: %s", ip, reason)
+		closeConn(conn, websocket.CloseTryAgainLater, reason)
+		return
+	}
 
-	// 注册客户端
-	HubInstance.register <- client
+	// 订阅容器日志时按握手时认证的用户检查权限
+	client := NewClient(HubInstance, conn, ip, middleware.CurrentPrincipal(c))
+
+	// 注册客户端，并唤醒默认主题的采集器立即推送第一份数据
+	HubInstance.Register(client)
+	for _, topic := range defaultTopics {
+		wakeCollector(topic)
+	}
 
 	// 启动读写 goroutine
+	HubInstance.pumps.Add(1)
 	go client.WritePump()
 	go client.ReadPump()
 }
+
+// CloseClients 断开所有 WebSocket 和 SSE 客户端并拒绝新连接
+// 在 http.Server.Shutdown 开始时调用，使长连接的 SSE 请求能够结束
+func CloseClients() {
+	if HubInstance != nil {
+		HubInstance.Close()
+	}
+}
+
+// DisconnectClients 断开所有 WebSocket 和 SSE 客户端，客户端重新连接时需要通过认证
+// 认证配置热加载后调用，已建立的连接不会继续以旧的身份或权限接收数据
+func DisconnectClients() {
+	if HubInstance != nil {
+		HubInstance.Disconnect(websocket.ClosePolicyViolation, reasonAuthRequired)
+	}
+}
+
+// Wait 等待采集器、日志跟踪和所有写协程退出，需在根 context 取消并调用 CloseClients 之后调用
+func Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		if HubInstance != nil {
+			HubInstance.pumps.Wait()
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// closeConn 发送关闭帧并关闭连接
+func closeConn(conn *websocket.Conn, code int, reason string) {
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(writeWait))
+	conn.Close()
+}
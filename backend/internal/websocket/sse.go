@@ -0,0 +1,194 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/auth"
+	"github.com/dat-G/MLServer_Dash/backend/internal/middleware"
+)
+
+const (
+	// sseHeartbeat 心跳注释的发送间隔，防止代理因空闲断开连接
+	sseHeartbeat = 15 * time.Second
+	// sseRetry 建议浏览器断线后的重连间隔（毫秒）
+	sseRetry = 3000
+)
+
+// HandleStream 以 Server-Sent Events 推送与 WebSocket 相同的主题，供无法升级 WebSocket 的代理环境使用
+//
+//	GET /api/stream?topics=system,gpu&interval=1000
+//
+// 每个主题对应一种事件，data 与 WebSocket 消息相同。客户端与 WebSocket 客户端共用 hub 的分发和采集器；
+// 重连时根据 Last-Event-ID 回放错过的消息
+func HandleStream(c *gin.Context) {
+	if HubInstance == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"detail": "stream not available"})
+		return
+	}
+
+	topics := defaultTopics
+	if raw := c.Query("topics"); raw != "" {
+		topics = nil
+		for _, topic := range strings.Split(raw, ",") {
+			topic = strings.TrimSpace(topic)
+			if topic == "" {
+				continue
+			}
+			if err := validateTopic(topic); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"detail": fmt.Sprintf("%s: %v", topic, err)})
+				return
+			}
+			topics = append(topics, topic)
+		}
+		if len(topics) == 0 || len(topics) > maxSubscriptions {
+			c.JSON(http.StatusBadRequest, gin.H{"detail": fmt.Sprintf("topics must list 1 to %d topics", maxSubscriptions)})
+			return
+		}
+	}
+
+	var interval time.Duration
+	if raw := c.Query("interval"); raw != "" {
+		ms, err := strconv.Atoi(raw)
+		if err != nil || ms <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"detail": "invalid interval"})
+			return
+		}
+		interval = clampInterval(time.Duration(ms) * time.Millisecond)
+	}
+
+	principal := middleware.CurrentPrincipal(c)
+	for _, topic := range topics {
+		if container, ok := logsContainer(topic); ok {
+			if err := authorizeLogs(principal, container); err != nil {
+				c.JSON(http.StatusForbidden, gin.H{"detail": fmt.Sprintf("%s: %v", topic, err)})
+				return
+			}
+		}
+	}
+
+	// 浏览器重连时自动带上 Last-Event-ID，无法解析时视为新连接
+	lastEventID, _ := strconv.ParseUint(c.GetHeader("Last-Event-ID"), 10, 64)
+
+	ip := c.ClientIP()
+	if reason, ok := limiter.Acquire(ip); !ok {
+		log.Printf("SSE: rejected connection from %s: %s", ip, reason)
+		c.Header("Retry-After", "10")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"detail": reason})
+		return
+	}
+	defer limiter.Release(ip)
+
+	client := newStreamClient(HubInstance, ip, principal, topics, interval)
+	HubInstance.Register(client)
+	defer HubInstance.Unregister(client)
+
+	for _, topic := range topics {
+		wakeCollector(topic)
+		if container, ok := logsContainer(topic); ok {
+			startLogStream(HubInstance, container)
+		}
+	}
+
+	w := c.Writer
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // 关闭 nginx 的响应缓冲
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetry)
+
+	// 先回放历史消息；注册之后广播的消息可能同时出现在历史和发送队列中，按序号去重
+	var lastSent uint64
+	if lastEventID > 0 {
+		for _, message := range HubInstance.replay(lastEventID) {
+			if !client.IsSubscribed(message.Type) {
+				continue
+			}
+			if err := writeEvent(w, message); err != nil {
+				return
+			}
+			lastSent = message.id
+		}
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-client.send.ready:
+			for _, message := range client.send.drain() {
+				if message.id != 0 && message.id <= lastSent {
+					continue
+				}
+				if err := writeEvent(w, message); err != nil {
+					return
+				}
+			}
+			w.Flush()
+		case <-client.send.done:
+			// hub 因客户端跟不上推送移除了客户端
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			w.Flush()
+		}
+	}
+}
+
+// newStreamClient 创建 SSE 客户端，没有 WebSocket 连接，订阅在连接时确定
+func newStreamClient(hub *Hub, ip string, principal *auth.Principal, topics []string, interval time.Duration) *Client {
+	c := &Client{
+		hub:       hub,
+		send:      newSendQueue(sendQueueSize),
+		ip:        ip,
+		codec:     jsonCodec,
+		principal: principal,
+		topics:    make(map[string]*subscription),
+	}
+	for _, topic := range topics {
+		c.topics[topic] = &subscription{interval: interval}
+	}
+	return c
+}
+
+// writeEvent 写入一条 SSE 事件，事件名为主题，广播消息使用共享的 JSON 编码
+func writeEvent(w gin.ResponseWriter, message Message) error {
+	var data []byte
+	if message.frames != nil {
+		frame := message.frames.encode(message, jsonCodec)
+		if frame.err != nil {
+			log.Printf("Failed to marshal message: %v", frame.err)
+			return nil
+		}
+		data = frame.data
+	} else {
+		var err error
+		if data, err = json.Marshal(message); err != nil {
+			log.Printf("Failed to marshal message: %v", err)
+			return nil
+		}
+	}
+
+	var b strings.Builder
+	if message.id != 0 {
+		fmt.Fprintf(&b, "id: %d\n", message.id)
+	}
+	fmt.Fprintf(&b, "event: %s\ndata: %s\n\n", message.Type, data)
+	_, err := w.WriteString(b.String())
+	return err
+}
@@ -0,0 +1,85 @@
+package websocket
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/dat-G/MLServer_Dash/backend/internal/auth"
+	"github.com/dat-G/MLServer_Dash/backend/internal/middleware"
+)
+
+// TopicDrops 单个主题的丢弃统计
+type TopicDrops struct {
+	Dropped uint64 `json:"dropped"`
+	Evicted uint64 `json:"evicted"`
+}
+
+// StatsResponse /api/ws/stats 的响应
+type StatsResponse struct {
+	HubStats
+	Topics map[string]TopicDrops `json:"topics"` // 按主题统计的丢弃和断开次数，日志主题合并为 logs
+}
+
+// logsStatsTopic 所有日志主题合并统计时使用的名称
+const logsStatsTopic = "logs"
+
+// dropCounter 通过 hub 的丢弃回调按主题累计丢弃和断开次数
+// logs:<容器> 主题随容器变化，合并为 logs 统计，避免计数表随订阅过的容器无限增长
+type dropCounter struct {
+	mu     sync.Mutex
+	topics map[string]TopicDrops
+}
+
+func newDropCounter() *dropCounter {
+	return &dropCounter{topics: make(map[string]TopicDrops)}
+}
+
+// record 作为 DropHook 使用
+func (d *dropCounter) record(topic, ip string, evicted bool) {
+	if _, ok := logsContainer(topic); ok {
+		topic = logsStatsTopic
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	drops := d.topics[topic]
+	drops.Dropped++
+	if evicted {
+		drops.Evicted++
+	}
+	d.topics[topic] = drops
+}
+
+func (d *dropCounter) snapshot() map[string]TopicDrops {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	topics := make(map[string]TopicDrops, len(d.topics))
+	for topic, drops := range d.topics {
+		topics[topic] = drops
+	}
+	return topics
+}
+
+// drops 全局丢弃统计，由 InitHub 接入 hub
+var drops = newDropCounter()
+
+// HandleStats 返回 WebSocket 和 SSE 推送的运行统计（仅 admin）
+//
+//	GET /api/ws/stats
+func HandleStats(c *gin.Context) {
+	if err := auth.RequireRole(middleware.CurrentPrincipal(c), auth.RoleAdmin); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"detail": err.Error()})
+		return
+	}
+	if HubInstance == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"detail": "stream not available"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StatsResponse{
+		HubStats: HubInstance.Stats(),
+		Topics:   drops.snapshot(),
+	})
+}
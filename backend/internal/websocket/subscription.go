@@ -0,0 +1,310 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// subscription 单个主题的订阅状态
+type subscription struct {
+	interval time.Duration // 客户端请求的推送间隔，0 表示使用默认间隔
+	lastSent time.Time
+
+	delta bool        // 是否使用增量推送
+	seq   uint64      // 已发送的最后一条消息序号
+	last  interface{} // 增量模式下上次发送的完整状态（JSON 通用结构），nil 表示下次发送快照
+}
+
+// clientRequest 客户端发送的消息，interval 为可选的推送间隔（毫秒），delta 开启增量推送
+//
+//	{"type": "subscribe", "topics": ["gpu", "logs:trainer"], "interval": 1000}
+//	{"type": "subscribe", "topics": ["system"], "delta": true}
+//	{"type": "unsubscribe", "topics": ["docker"]}
+//	{"type": "resync", "topics": ["system"]}
+type clientRequest struct {
+	Type     string   `json:"type"`
+	Topics   []string `json:"topics"`
+	Interval int      `json:"interval,omitempty"`
+	Delta    *bool    `json:"delta,omitempty"`
+}
+
+// subscriptionStatus subscribed 消息的内容
+type subscriptionStatus struct {
+	Topics    []string       `json:"topics"`
+	Intervals map[string]int `json:"intervals"` // 各周期性主题实际生效的推送间隔（毫秒）
+	Delta     []string       `json:"delta"`     // 使用增量推送的主题
+}
+
+// errorStatus error 消息的内容
+type errorStatus struct {
+	Message string `json:"message"`
+	Topic   string `json:"topic,omitempty"`
+}
+
+// intervalTolerance 推送间隔的容差比例，吸收采集时间的抖动
+const intervalTolerance = 10
+
+// IsSubscribed 客户端是否订阅了 topic
+func (c *Client) IsSubscribed(topic string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.topics[topic]
+	return ok
+}
+
+// subscribedTopics 返回已订阅主题的集合
+func (c *Client) subscribedTopics() map[string]struct{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	topics := make(map[string]struct{}, len(c.topics))
+	for topic := range c.topics {
+		topics[topic] = struct{}{}
+	}
+	return topics
+}
+
+// interval 客户端对 topic 生效的推送间隔，未订阅时返回 0
+func (c *Client) interval(topic string) time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	sub, ok := c.topics[topic]
+	if !ok {
+		return 0
+	}
+	return effectiveInterval(sub)
+}
+
+// shouldDeliver 判断是否向客户端推送该主题的本次数据，推送时记录时间
+// 采集频率高于客户端请求的间隔时按客户端间隔降采样；事件类主题每条都推送
+func (c *Client) shouldDeliver(topic string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sub, ok := c.topics[topic]
+	if !ok {
+		return false
+	}
+	if !isPeriodicTopic(topic) {
+		return true
+	}
+
+	interval := effectiveInterval(sub)
+	if !sub.lastSent.IsZero() && now.Sub(sub.lastSent) < interval-interval/intervalTolerance {
+		return false
+	}
+	sub.lastSent = now
+	return true
+}
+
+// status 返回当前订阅状态
+func (c *Client) status() subscriptionStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	st := subscriptionStatus{
+		Topics:    make([]string, 0, len(c.topics)),
+		Intervals: make(map[string]int),
+		Delta:     []string{},
+	}
+	for topic, sub := range c.topics {
+		st.Topics = append(st.Topics, topic)
+		if isPeriodicTopic(topic) {
+			st.Intervals[topic] = int(effectiveInterval(sub).Milliseconds())
+		}
+		if sub.delta {
+			st.Delta = append(st.Delta, topic)
+		}
+	}
+	sort.Strings(st.Topics)
+	sort.Strings(st.Delta)
+	return st
+}
+
+// handleRequest 处理客户端发送的订阅请求
+// 文本帧按 JSON 解析，二进制帧按协商的子协议解析
+func (c *Client) handleRequest(frameType int, data []byte) {
+	var req clientRequest
+	unmarshal := json.Unmarshal
+	if frameType == websocket.BinaryMessage {
+		unmarshal = c.codec.unmarshal
+	}
+	if err := unmarshal(data, &req); err != nil {
+		c.hub.sendTo(c, Message{Type: "error", Data: errorStatus{Message: "invalid message"}})
+		return
+	}
+
+	switch req.Type {
+	case "ping":
+		c.hub.sendTo(c, Message{Type: "pong"})
+		return
+	case "resync":
+		c.resync(req.Topics)
+		return
+	case "subscribe", "unsubscribe":
+	default:
+		c.hub.sendTo(c, Message{Type: "error", Data: errorStatus{Message: fmt.Sprintf("unknown message type %q", req.Type)}})
+		return
+	}
+
+	var interval time.Duration
+	if req.Interval > 0 {
+		interval = clampInterval(time.Duration(req.Interval) * time.Millisecond)
+	}
+
+	var errs []errorStatus
+	var changed []string
+
+	// 新订阅的日志主题先检查权限，查询容器信息时不持有锁
+	denied := make(map[string]bool)
+	if req.Type == "subscribe" {
+		for _, topic := range req.Topics {
+			container, ok := logsContainer(topic)
+			if !ok || validateTopic(topic) != nil || c.IsSubscribed(topic) {
+				continue
+			}
+			if err := authorizeLogs(c.principal, container); err != nil {
+				errs = append(errs, errorStatus{Message: err.Error(), Topic: topic})
+				denied[topic] = true
+			}
+		}
+	}
+
+	c.mu.Lock()
+	for _, topic := range req.Topics {
+		if err := validateTopic(topic); err != nil {
+			errs = append(errs, errorStatus{Message: err.Error(), Topic: topic})
+			continue
+		}
+		if denied[topic] {
+			continue
+		}
+		sub, subscribed := c.topics[topic]
+		switch {
+		case req.Type == "unsubscribe":
+			delete(c.topics, topic)
+			changed = append(changed, topic)
+		case subscribed:
+			// 重复订阅用于修改推送间隔或增量模式
+			if req.Interval > 0 {
+				sub.interval = interval
+				changed = append(changed, topic)
+			}
+			if req.Delta != nil && *req.Delta != sub.delta && isPeriodicTopic(topic) {
+				sub.delta = *req.Delta
+				sub.last = nil
+			}
+		case len(c.topics) >= maxSubscriptions:
+			errs = append(errs, errorStatus{Message: "too many subscriptions", Topic: topic})
+		default:
+			sub = &subscription{interval: interval}
+			if req.Delta != nil && isPeriodicTopic(topic) {
+				sub.delta = *req.Delta
+			}
+			c.topics[topic] = sub
+			changed = append(changed, topic)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, e := range errs {
+		c.hub.sendTo(c, Message{Type: "error", Data: e})
+	}
+
+	// 更新 hub 的主题索引，并通知采集器按新的最快间隔调度
+	c.hub.syncTopics(c)
+	for _, topic := range changed {
+		wakeCollector(topic)
+	}
+
+	// 订阅容器日志时按需启动日志跟踪；跟踪因容器停止而结束时，重新订阅即可重新开始
+	if req.Type == "subscribe" {
+		for _, topic := range req.Topics {
+			if container, ok := logsContainer(topic); ok && c.IsSubscribed(topic) {
+				startLogStream(c.hub, container)
+			}
+		}
+	}
+
+	c.hub.sendTo(c, Message{Type: "subscribed", Data: c.status()})
+}
+
+// resync 客户端发现序号不连续时请求重新同步
+// 立即重发上次的完整状态作为快照；尚未发送过数据的主题在下次推送时发送快照
+func (c *Client) resync(topics []string) {
+	var snapshots []Message
+	c.mu.Lock()
+	for _, topic := range topics {
+		sub, ok := c.topics[topic]
+		if !ok || !sub.delta || sub.last == nil {
+			continue
+		}
+		snapshots = append(snapshots, Message{Type: topic, Data: sub.last, resync: true})
+	}
+	c.mu.Unlock()
+
+	for _, msg := range snapshots {
+		c.hub.sendTo(c, msg)
+	}
+}
+
+// encodeDelta 在写入前将增量模式主题的消息转换为快照或补丁
+// 返回 false 表示数据没有变化，不需要发送
+func (c *Client) encodeDelta(msg Message) (Message, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sub, ok := c.topics[msg.Type]
+	if !ok || !sub.delta {
+		return msg, true
+	}
+
+	if msg.resync {
+		sub.seq++
+		return Message{Type: msg.Type, Seq: sub.seq, Snapshot: true, Data: msg.Data}, true
+	}
+
+	current, err := toGeneric(msg.Data)
+	if err != nil {
+		return msg, true
+	}
+
+	var ops []PatchOp
+	if sub.last != nil {
+		ops = diffJSON("", sub.last, current, nil)
+		if len(ops) == 0 {
+			return msg, false
+		}
+	}
+	sub.last = current
+	sub.seq++
+
+	// 首次发送或整体替换时直接发送快照
+	if ops == nil || (len(ops) == 1 && ops[0].Path == "") {
+		return Message{Type: msg.Type, Seq: sub.seq, Snapshot: true, Data: current}, true
+	}
+	return Message{Type: msg.Type, Seq: sub.seq, Patch: ops}, true
+}
+
+// effectiveInterval 订阅实际生效的推送间隔
+func effectiveInterval(sub *subscription) time.Duration {
+	if sub.interval > 0 {
+		return sub.interval
+	}
+	return currentSettings().defaultInterval
+}
+
+// clampInterval 将客户端请求的间隔限制在配置的范围内
+func clampInterval(d time.Duration) time.Duration {
+	ws := currentSettings().ws
+	if minInterval := time.Duration(ws.MinInterval) * time.Millisecond; minInterval > 0 && d < minInterval {
+		return minInterval
+	}
+	if maxInterval := time.Duration(ws.MaxInterval) * time.Millisecond; maxInterval > 0 && d > maxInterval {
+		return maxInterval
+	}
+	return d
+}
@@ -0,0 +1,61 @@
+package websocket
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 可订阅的主题
+const (
+	TopicSystem    = "system"    // 完整系统信息
+	TopicGPU       = "gpu"       // 仅 GPU 信息
+	TopicDocker    = "docker"    // 容器列表
+	TopicProcesses = "processes" // CPU 占用最高的进程
+	TopicAlerts    = "alerts"    // 告警事件，预留给尚未实现的告警模块，目前没有发布者
+)
+
+// logsTopicPrefix 容器日志主题前缀，完整主题为 logs:<容器 ID 或名称>
+const logsTopicPrefix = "logs:"
+
+// maxSubscriptions 每个连接最多订阅的主题数
+const maxSubscriptions = 32
+
+// defaultTopics 新连接默认订阅的主题，与旧版本推送的内容一致
+var defaultTopics = []string{TopicSystem, TopicDocker}
+
+// knownTopics 固定主题
+var knownTopics = map[string]bool{
+	TopicSystem:    true,
+	TopicGPU:       true,
+	TopicDocker:    true,
+	TopicProcesses: true,
+	TopicAlerts:    true,
+}
+
+// isPeriodicTopic 是否为定期采集的主题，只有这类主题支持设置推送间隔
+func isPeriodicTopic(topic string) bool {
+	switch topic {
+	case TopicSystem, TopicGPU, TopicDocker, TopicProcesses:
+		return true
+	}
+	return false
+}
+
+// validateTopic 检查主题名称
+func validateTopic(topic string) error {
+	if knownTopics[topic] {
+		return nil
+	}
+	if container, ok := logsContainer(topic); ok {
+		if container == "" || strings.ContainsAny(container, " /") {
+			return fmt.Errorf("invalid container in topic %q", topic)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown topic %q", topic)
+}
+
+// logsContainer 从日志主题中取出容器 ID 或名称
+func logsContainer(topic string) (string, bool) {
+	return strings.CutPrefix(topic, logsTopicPrefix)
+}
@@ -16,11 +16,12 @@ import {
   Rss,
 } from 'lucide-react'
 import { Gpu } from 'lucide-react'
-import configJson from '../../config.json'
+// 只导入需要的配置段，避免 auth 等后端配置被打包进前端
+import { app as appConfig, server as serverConfig } from '../../config.json'
 
-const APP_TITLE = configJson.app.appName
-const GITHUB_URL = configJson.app.githubUrl
-const HISTORY_SIZE = configJson.server.historySize || 30
+const APP_TITLE = appConfig.appName
+const GITHUB_URL = appConfig.githubUrl
+const HISTORY_SIZE = serverConfig.historySize || 30
 
 const COLORS = {
   blue: '#00d4ff',
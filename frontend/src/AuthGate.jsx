@@ -0,0 +1,125 @@
+import { useState, useEffect } from 'react'
+import { Server, RefreshCw, LogIn, KeyRound } from 'lucide-react'
+import { app as appConfig } from '../../config.json'
+
+const API_BASE = '/api'
+
+// 认证守卫：后端启用认证且未登录时显示登录表单
+function AuthGate({ children }) {
+  const [status, setStatus] = useState('checking') // checking | ok | login
+  const [username, setUsername] = useState('')
+  const [password, setPassword] = useState('')
+  const [error, setError] = useState('')
+  const [submitting, setSubmitting] = useState(false)
+  const [providers, setProviders] = useState({ local: true, oidc: false })
+
+  useEffect(() => {
+    // SSO 回调失败时后端带 sso_error 参数跳回首页
+    const params = new URLSearchParams(window.location.search)
+    const ssoError = params.get('sso_error')
+    if (ssoError) {
+      setError(ssoError)
+      window.history.replaceState(null, '', window.location.pathname)
+    }
+
+    fetch(`${API_BASE}/auth/providers`, { credentials: 'same-origin' })
+      .then(response => response.json())
+      .then(data => setProviders(data))
+      .catch(() => {})
+
+    fetch(`${API_BASE}/auth/me`, { credentials: 'same-origin' })
+      .then(response => setStatus(response.status === 401 ? 'login' : 'ok'))
+      .catch(() => setStatus('ok'))
+  }, [])
+
+  const handleSubmit = async (e) => {
+    e.preventDefault()
+    setSubmitting(true)
+    setError('')
+    try {
+      const response = await fetch(`${API_BASE}/auth/login`, {
+        method: 'POST',
+        credentials: 'same-origin',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ username, password }),
+      })
+      if (response.ok) {
+        setPassword('')
+        setStatus('ok')
+      } else {
+        const data = await response.json().catch(() => ({}))
+        setError(data.detail || '登录失败')
+      }
+    } catch (err) {
+      setError('无法连接服务器')
+    } finally {
+      setSubmitting(false)
+    }
+  }
+
+  if (status === 'checking') {
+    return (
+      <div className="min-h-screen flex items-center justify-center bg-cyber-black">
+        <RefreshCw className="w-12 h-12 animate-spin text-neon-blue" />
+      </div>
+    )
+  }
+
+  if (status === 'ok') {
+    return children
+  }
+
+  return (
+    <div className="min-h-screen flex items-center justify-center bg-cyber-black p-4">
+      <form onSubmit={handleSubmit} className="glass-card hover-glow-blue p-8 w-full max-w-sm">
+        <h1 className="text-2xl font-bold text-white mb-6 flex items-center">
+          <Server className="w-6 h-6 mr-2 text-neon-blue" />
+          {appConfig.appName}
+        </h1>
+        {providers.local && (
+          <>
+            <label className="block text-sm text-gray-400 mb-1" htmlFor="username">用户名</label>
+            <input
+              id="username"
+              className="w-full mb-4 px-3 py-2 rounded-lg bg-cyber-dark border border-cyber-border text-white font-mono focus:outline-none focus:border-neon-blue"
+              autoComplete="username"
+              value={username}
+              onChange={e => setUsername(e.target.value)}
+            />
+            <label className="block text-sm text-gray-400 mb-1" htmlFor="password">密码</label>
+            <input
+              id="password"
+              type="password"
+              className="w-full mb-4 px-3 py-2 rounded-lg bg-cyber-dark border border-cyber-border text-white font-mono focus:outline-none focus:border-neon-blue"
+              autoComplete="current-password"
+              value={password}
+              onChange={e => setPassword(e.target.value)}
+            />
+          </>
+        )}
+        {error && <p className="text-sm text-neon-red mb-4">{error}</p>}
+        {providers.local && (
+          <button
+            type="submit"
+            disabled={submitting}
+            className="w-full flex items-center justify-center gap-2 px-4 py-2 rounded-lg border border-neon-blue text-neon-blue hover:bg-neon-blue/10 disabled:opacity-50"
+          >
+            <LogIn className="w-4 h-4" />
+            登录
+          </button>
+        )}
+        {providers.oidc && (
+          <a
+            href={`${API_BASE}/auth/oidc/login`}
+            className="w-full mt-3 flex items-center justify-center gap-2 px-4 py-2 rounded-lg border border-neon-purple text-neon-purple hover:bg-neon-purple/10"
+          >
+            <KeyRound className="w-4 h-4" />
+            使用 {providers.oidc_name || 'SSO'} 登录
+          </a>
+        )}
+      </form>
+    </div>
+  )
+}
+
+export default AuthGate